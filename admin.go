@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// logAdminAction records an admin intervention in the event log. The caller holds g.mu.
+func (g *Game) logAdminAction(action string, details map[string]interface{}) {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["action"] = action
+	g.events.LogEvent(EventAdminAction, "", details)
+}
+
+// registerAdminRoutes adds the game control endpoints to the admin group
+func registerAdminRoutes(admin *gin.RouterGroup) {
+	admin.GET("/snapshot", getSnapshotHandler)
+	admin.GET("/map", exportMapHandler)
+	admin.GET("/render", renderMapHandler)
+	admin.POST("/pause", setPausedHandler(true))
+	admin.POST("/resume", setPausedHandler(false))
+	admin.POST("/tick", forceTickHandler)
+	admin.PUT("/turn-length/:seconds", setTurnLengthHandler)
+	admin.POST("/end", endGameHandler)
+
+	// World editing for scenario tests
+	admin.PUT("/tile/:x/:y", editTileHandler)
+	admin.PUT("/player/:id/position/:x/:y", teleportPlayerHandler)
+	admin.PUT("/player/:id/cards", setCardsHandler)
+	admin.POST("/player/:id/revive", revivePlayerHandler)
+}
+
+// setPausedHandler stops or restarts the turn timer. A paused game only
+// advances through POST /admin/tick.
+func setPausedHandler(paused bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		game := currentGame(c)
+		game.mu.Lock()
+		if game.state.isFinished() {
+			game.mu.Unlock()
+			sendErrorResponse(c, http.StatusConflict, "game_over", "Game is over")
+			return
+		}
+		game.state.paused = paused
+		if paused {
+			game.logAdminAction("pause", nil)
+		} else {
+			game.logAdminAction("resume", nil)
+		}
+		game.mu.Unlock()
+
+		game.wakeLoop()
+		sendSuccessResponse(c, http.StatusOK, gin.H{"game": game.summary()})
+	}
+}
+
+// forceTickHandler resolves the current turn right away. On a paused game this
+// steps exactly one tick, on a running game it cuts the turn short.
+func forceTickHandler(c *gin.Context) {
+	game := currentGame(c)
+	ticked := game.endTurnIf(func() bool {
+		if game.state.isFinished() {
+			return false
+		}
+		game.logAdminAction("tick", nil)
+		return true
+	})
+	if !ticked {
+		sendErrorResponse(c, http.StatusConflict, "game_over", "Game is over")
+		return
+	}
+	sendSuccessResponse(c, http.StatusOK, gin.H{"game": game.summary()})
+}
+
+// setTurnLengthHandler changes TurnLength for this and all following turns.
+// A running turn is shortened if it has more time left than the new length;
+// the time already played still counts towards MinTurnLength.
+func setTurnLengthHandler(c *gin.Context) {
+	seconds, err := strconv.Atoi(c.Param("seconds"))
+	if err != nil || seconds <= 0 {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_turn_length", "Turn length must be a positive number of seconds")
+		return
+	}
+
+	game := currentGame(c)
+	game.mu.Lock()
+	previous := game.config.Game.TurnLength
+	game.config.Game.TurnLength = seconds
+	if game.state.turnTimer > seconds {
+		game.state.turnTimer = seconds
+	}
+	game.logAdminAction("turn_length", map[string]interface{}{
+		"previous": previous,
+		"seconds":  seconds,
+	})
+	game.mu.Unlock()
+
+	game.wakeLoop()
+	sendSuccessResponse(c, http.StatusOK, gin.H{"game": game.summary()})
+}
+
+// endGameHandler stops the game loop for good. The game stays readable.
+func endGameHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.Lock()
+	game.state.end()
+	game.logAdminAction("end", nil)
+	game.saveSnapshot()
+	game.mu.Unlock()
+
+	game.wakeLoop()
+	sendSuccessResponse(c, http.StatusOK, gin.H{"game": game.summary()})
+}
+
+// TileEdit is the body of PUT /admin/tile/:x/:y. Fields left out are not changed.
+type TileEdit struct {
+	Terrain *Terrain
+	Zombies *int
+}
+
+// tilePosition parses the :x and :y parameters, answering 400 if they are off the map
+func tilePosition(c *gin.Context, game *Game) (int, int, bool) {
+	x, errX := strconv.Atoi(c.Param("x"))
+	y, errY := strconv.Atoi(c.Param("y"))
+	if errX != nil || errY != nil || !game.gMap.isOnMap(x, y) {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_position", "Position is not on the map")
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+// adminPlayer returns the player of the :id parameter, answering 404 if there is none.
+// The caller holds game.mu.
+func adminPlayer(c *gin.Context, game *Game) (*Player, bool) {
+	player := game.getPlayerOrNil(c.Param("id"))
+	if player == nil {
+		sendErrorResponse(c, http.StatusNotFound, "player_not_found", "Player not found")
+		return nil, false
+	}
+	return player, true
+}
+
+// editTileHandler sets the terrain and/or zombie count of a tile
+func editTileHandler(c *gin.Context) {
+	var edit TileEdit
+	if err := c.ShouldBindJSON(&edit); err != nil {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_body", err.Error())
+		return
+	}
+	if edit.Terrain != nil && (*edit.Terrain < Forest || *edit.Terrain >= Edge) {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_terrain", "Terrain must be forest, farm, city or laboratory")
+		return
+	}
+	if edit.Zombies != nil && *edit.Zombies < 0 {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_zombies", "Zombies must not be negative")
+		return
+	}
+
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	x, y, ok := tilePosition(c, game)
+	if !ok {
+		return
+	}
+
+	tile := game.gMap.getTileFromPos(x, y)
+	details := map[string]interface{}{"x": x, "y": y}
+	if edit.Terrain != nil {
+		details["previous_terrain"] = tile.Terrain.toString()
+		details["terrain"] = edit.Terrain.toString()
+		tile.Terrain = *edit.Terrain
+	}
+	if edit.Zombies != nil {
+		details["previous_zombies"] = tile.Zombies
+		details["zombies"] = *edit.Zombies
+		game.gMap.setZombiesOnTile(x, y, *edit.Zombies)
+	}
+	game.logAdminAction("edit_tile", details)
+
+	sendSuccessResponse(c, http.StatusOK, gin.H{"tile": gin.H{
+		"x":       x,
+		"y":       y,
+		"terrain": tile.Terrain.toString(),
+		"zombies": tile.Zombies,
+	}})
+}
+
+// teleportPlayerHandler moves a player to any tile of the map
+func teleportPlayerHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player, ok := adminPlayer(c, game)
+	if !ok {
+		return
+	}
+	x, y, ok := tilePosition(c, game)
+	if !ok {
+		return
+	}
+
+	fromX, fromY := player.CurrentTile.XPos, player.CurrentTile.YPos
+	game.pMap.teleport(player, x, y)
+	game.logAdminAction("teleport", map[string]interface{}{
+		"player_id": player.ID,
+		"from_x":    fromX,
+		"from_y":    fromY,
+		"x":         x,
+		"y":         y,
+	})
+	sendSuccessResponse(c, http.StatusOK, gin.H{"player": player})
+}
+
+// setCardsHandler replaces a player's hand with the cards in the body, a JSON
+// array of card names. Research handed out this way counts at every laboratory.
+func setCardsHandler(c *gin.Context) {
+	var cards []Card
+	if err := c.ShouldBindJSON(&cards); err != nil {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_body", err.Error())
+		return
+	}
+
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player, ok := adminPlayer(c, game)
+	if !ok {
+		return
+	}
+	if len(cards) > player.handCapacity() {
+		sendErrorResponse(c, http.StatusBadRequest, "too_many_cards",
+			fmt.Sprintf("A hand holds at most %d cards", player.handCapacity()))
+		return
+	}
+
+	previous := player.Cards
+	player.setHand(cards)
+	game.logAdminAction("set_cards", map[string]interface{}{
+		"player_id": player.ID,
+		"previous":  previous,
+		"cards":     player.Cards,
+	})
+	sendSuccessResponse(c, http.StatusOK, gin.H{"player": player})
+}
+
+// revivePlayerHandler brings a dead player back on its current tile
+func revivePlayerHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player, ok := adminPlayer(c, game)
+	if !ok {
+		return
+	}
+
+	wasAlive := player.Alive
+	player.Alive = true
+	game.logAdminAction("revive", map[string]interface{}{
+		"player_id": player.ID,
+		"was_alive": wasAlive,
+	})
+	sendSuccessResponse(c, http.StatusOK, gin.H{"player": player})
+}
+
+// exportMapHandler returns the current map in the map file format, so it can be
+// saved and played again through Map.File
+func exportMapHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.RLock()
+	m := game.gMap.mapFile()
+	game.mu.RUnlock()
+
+	var out bytes.Buffer
+	if err := m.Write(&out); err != nil {
+		sendErrorResponse(c, http.StatusInternalServerError, "export_failed", err.Error())
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", game.ID+".map"))
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", out.Bytes())
+}
+
+// renderMapHandler draws the map as text. The query parameters x, y, width and
+// height select a window, ascii=true uses letters instead of emoji.
+func renderMapHandler(c *gin.Context) {
+	var window MapWindow
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{{"x", &window.X}, {"y", &window.Y}, {"width", &window.Width}, {"height", &window.Height}} {
+		if text := c.Query(param.name); text != "" {
+			n, err := strconv.Atoi(text)
+			if err != nil {
+				sendErrorResponse(c, http.StatusBadRequest, "invalid_window", fmt.Sprintf("%s must be a number", param.name))
+				return
+			}
+			*param.value = n
+		}
+	}
+	ascii := c.Query("ascii") == "true"
+
+	game := currentGame(c)
+	var out bytes.Buffer
+	game.mu.RLock()
+	err := game.gMap.render(&out, window, ascii)
+	game.mu.RUnlock()
+	if err != nil {
+		sendErrorResponse(c, http.StatusInternalServerError, "render_failed", err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", out.Bytes())
+}
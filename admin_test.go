@@ -0,0 +1,239 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAdminTestSuite returns an API suite whose default game has the admin token "secret"
+func setupAdminTestSuite(t *testing.T) (*APITestSuite, *Game) {
+	as := setupAPITestSuite(t)
+	game := as.registry.get(defaultGameID)
+	game.config.Server.AdminToken = "secret"
+	game.quiet = true
+	return as, game
+}
+
+func TestAdminControl(t *testing.T) {
+	t.Run("endpoints require the admin token", func(t *testing.T) {
+		as, _ := setupAdminTestSuite(t)
+
+		for _, endpoint := range []struct{ method, path string }{
+			{http.MethodPost, "/admin/pause"},
+			{http.MethodPost, "/admin/resume"},
+			{http.MethodPost, "/admin/tick"},
+			{http.MethodPut, "/admin/turn-length/5"},
+			{http.MethodPost, "/admin/end"},
+		} {
+			assert.Equal(t, http.StatusUnauthorized, as.request(endpoint.method, endpoint.path, "").Code,
+				"%s %s without token", endpoint.method, endpoint.path)
+		}
+	})
+
+	t.Run("paused game keeps its timer until resumed", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		require.Equal(t, http.StatusOK, as.requestWithToken(http.MethodPost, "/admin/pause", "", "secret").Code)
+		timer := game.state.turnTimer
+
+		game.timerDown()
+
+		assert.Equal(t, timer, game.state.turnTimer)
+		assert.True(t, game.summary().Paused)
+
+		require.Equal(t, http.StatusOK, as.requestWithToken(http.MethodPost, "/admin/resume", "", "secret").Code)
+		game.timerDown()
+		assert.Equal(t, timer-1, game.state.turnTimer)
+	})
+
+	t.Run("paused game does not end its turn", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		as.requestWithToken(http.MethodPost, "/admin/pause", "", "secret")
+		game.state.turnTimer = 0
+
+		assert.False(t, game.canEndTurn())
+	})
+
+	t.Run("tick steps a paused game by exactly one turn", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		as.requestWithToken(http.MethodPost, "/admin/pause", "", "secret")
+		remaining := game.state.getRemainingTurns()
+
+		rec := as.requestWithToken(http.MethodPost, "/admin/tick", "", "secret")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response struct{ Game GameSummary }
+		as.decode(rec, &response)
+		assert.Equal(t, remaining-1, response.Game.RemainingTurns)
+		assert.True(t, response.Game.Paused, "Stepping should not resume the game")
+		assert.Equal(t, int64(1), game.state.getTurn())
+	})
+
+	t.Run("turn length changes on the fly", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+
+		rec := as.requestWithToken(http.MethodPut, "/admin/turn-length/4", "", "secret")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 4, game.config.Game.TurnLength)
+		assert.Equal(t, 4, game.state.turnTimer, "Running turn should be cut to the new length")
+		game.endTurn()
+		assert.Equal(t, 4, game.state.turnTimer)
+
+		assert.Equal(t, http.StatusBadRequest, as.requestWithToken(http.MethodPut, "/admin/turn-length/0", "", "secret").Code)
+		assert.Equal(t, http.StatusBadRequest, as.requestWithToken(http.MethodPut, "/admin/turn-length/soon", "", "secret").Code)
+	})
+
+	t.Run("turn length changes keep the minimum turn length", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		game.config.Game.MinTurnLength = 3
+		as.addPlayer("", "Alice")
+		game.pMap.sortedPlayers()[0].Ready = true
+		game.timerDown()
+
+		as.requestWithToken(http.MethodPut, "/admin/turn-length/100", "", "secret")
+
+		assert.Equal(t, 1, game.state.elapsed(), "A longer turn should not count as time passed")
+		assert.False(t, game.canEndTurn())
+		game.timerDown()
+		game.timerDown()
+		assert.True(t, game.canEndTurn())
+	})
+
+	t.Run("ending the game stops the loop", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		done := make(chan struct{})
+		go func() {
+			game.run()
+			close(done)
+		}()
+
+		require.Equal(t, http.StatusOK, as.requestWithToken(http.MethodPost, "/admin/end", "", "secret").Code)
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Game loop did not stop")
+		}
+		assert.True(t, game.summary().Ended)
+		assert.Equal(t, http.StatusConflict, as.requestWithToken(http.MethodPost, "/admin/tick", "", "secret").Code)
+		assert.Equal(t, http.StatusConflict, as.requestWithToken(http.MethodPost, "/admin/resume", "", "secret").Code)
+	})
+
+	t.Run("admin actions are logged", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		as.requestWithToken(http.MethodPost, "/admin/pause", "", "secret")
+		as.requestWithToken(http.MethodPut, "/admin/turn-length/9", "", "secret")
+
+		events := game.events.GetPlayerEvents("", EventFilters{EventType: EventAdminAction})
+
+		require.Len(t, events, 2)
+		assert.Equal(t, "pause", events[0].Details["action"])
+		assert.Equal(t, "turn_length", events[1].Details["action"])
+		assert.Equal(t, 9, events[1].Details["seconds"])
+	})
+}
+
+func TestAdminWorldEditing(t *testing.T) {
+	t.Run("tile terrain and zombies can be set", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		cutoff := game.config.Combat.ZombieCutoff
+
+		rec := as.requestWithToken(http.MethodPut, "/admin/tile/2/3", `{"Terrain": "city", "Zombies": `+strconv.Itoa(cutoff+5)+`}`, "secret")
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		tile := game.gMap.getTileFromPos(2, 3)
+		assert.Equal(t, City, tile.Terrain)
+		assert.Equal(t, cutoff+5, tile.Zombies, "Admins may exceed the zombie cutoff")
+
+		rec = as.requestWithToken(http.MethodPut, "/admin/tile/2/3", `{"Zombies": 1}`, "secret")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 1, tile.Zombies)
+		assert.Equal(t, City, tile.Terrain, "Terrain should be kept when left out")
+	})
+
+	t.Run("invalid tile edits are rejected", func(t *testing.T) {
+		as, _ := setupAdminTestSuite(t)
+
+		for _, tc := range []struct{ path, body string }{
+			{"/admin/tile/-1/0", `{"Zombies": 1}`},
+			{"/admin/tile/0/9999", `{"Zombies": 1}`},
+			{"/admin/tile/0/0", `{"Zombies": -1}`},
+			{"/admin/tile/0/0", `{"Terrain": "edge"}`},
+			{"/admin/tile/0/0", `{"Terrain": "swamp"}`},
+		} {
+			assert.Equal(t, http.StatusBadRequest, as.requestWithToken(http.MethodPut, tc.path, tc.body, "secret").Code,
+				"%s %s", tc.path, tc.body)
+		}
+	})
+
+	t.Run("teleport keeps tile and player in sync", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		player := as.addPlayer("", "Alice")
+		ptr := game.getPlayerOrNil(player.ID)
+		oldTile := ptr.CurrentTile
+
+		rec := as.requestWithToken(http.MethodPut, "/admin/player/"+player.ID+"/position/4/5", "", "secret")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		newTile := game.gMap.getTileFromPos(4, 5)
+		assert.Same(t, newTile, ptr.CurrentTile)
+		assert.Contains(t, newTile.playerPtrs, ptr)
+		assert.NotContains(t, oldTile.playerPtrs, ptr)
+
+		moves := game.events.GetPlayerEvents(player.ID, EventFilters{EventType: EventPlayerMove})
+		require.NotEmpty(t, moves)
+		assert.Equal(t, "teleported", moves[len(moves)-1].Details["reason"])
+
+		assert.Equal(t, http.StatusNotFound, as.requestWithToken(http.MethodPut, "/admin/player/nobody/position/4/5", "", "secret").Code)
+	})
+
+	t.Run("cards can be replaced", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		player := as.addPlayer("", "Alice")
+
+		rec := as.requestWithToken(http.MethodPut, "/admin/player/"+player.ID+"/cards", `["research", "Weapon"]`, "secret")
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		ptr := game.getPlayerOrNil(player.ID)
+		assert.Equal(t, []Card{Research, Weapon}, ptr.Cards)
+		assert.Equal(t, 1, ptr.usableResearchAt(ptr.CurrentTile.XPos, ptr.CurrentTile.YPos),
+			"Handed out research should count everywhere")
+
+		assert.Equal(t, http.StatusBadRequest,
+			as.requestWithToken(http.MethodPut, "/admin/player/"+player.ID+"/cards", `["food","food","food","food","food","food"]`, "secret").Code)
+		assert.Equal(t, http.StatusBadRequest,
+			as.requestWithToken(http.MethodPut, "/admin/player/"+player.ID+"/cards", `["gold"]`, "secret").Code)
+	})
+
+	t.Run("dead player can be revived", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		player := as.addPlayer("", "Alice")
+		game.getPlayerOrNil(player.ID).Alive = false
+
+		rec := as.requestWithToken(http.MethodPost, "/admin/player/"+player.ID+"/revive", "", "secret")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, game.getPlayerOrNil(player.ID).Alive)
+	})
+
+	t.Run("edits are audited", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		player := as.addPlayer("", "Alice")
+		as.requestWithToken(http.MethodPut, "/admin/tile/1/1", `{"Zombies": 2}`, "secret")
+		as.requestWithToken(http.MethodPut, "/admin/player/"+player.ID+"/position/1/1", "", "secret")
+		as.requestWithToken(http.MethodPut, "/admin/player/"+player.ID+"/cards", `["food"]`, "secret")
+		as.requestWithToken(http.MethodPost, "/admin/player/"+player.ID+"/revive", "", "secret")
+
+		var actions []interface{}
+		for _, event := range game.events.GetPlayerEvents("", EventFilters{EventType: EventAdminAction}) {
+			actions = append(actions, event.Details["action"])
+		}
+
+		assert.Equal(t, []interface{}{"edit_tile", "teleport", "set_cards", "revive"}, actions)
+	})
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -10,8 +14,13 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func setupAPI() {
-	router := gin.Default()
+func setupAPI(registry *GameRegistry) {
+	newRouter(registry).Run("0.0.0.0:8080")
+}
+
+func newRouter(registry *GameRegistry) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.LoggerWithFormatter(requestLogFormatter), gin.Recovery())
 
 	// Configure CORS to allow all origins for local development
 	config := cors.DefaultConfig()
@@ -23,62 +32,172 @@ func setupAPI() {
 	// Add middleware for error handling and logging
 	router.Use(errorHandlingMiddleware())
 
-	router.GET("/player/:id", getPlayerHandler)
-	router.GET("/player/:id/surroundings", getSurroundingsHandler)
-	router.GET("/config", getAllConfigHandler)
-	router.POST("/player/:name", addPlayerHandler)
-	router.PUT("/player/:id/direction/:dir", setDirectionHandler)
-	router.PUT("/player/:id/play/:cardType", setPlayHandler)
+	// Routes without a game prefix address the default game
+	registerGameRoutes(router.Group("/", gameMiddleware(registry)))
+
+	router.GET("/games", listGamesHandler(registry))
+	router.POST("/games", createGameHandler(registry))
+	registerGameRoutes(router.Group("/games/:gameId", gameMiddleware(registry)))
+
+	return router
+}
+
+// registerGameRoutes adds the endpoints of a single game to group
+func registerGameRoutes(group *gin.RouterGroup) {
+	group.GET("/config", getAllConfigHandler)
+	group.POST("/player/:name", addPlayerHandler)
+
+	// Everything about a single player requires that player's token
+	playerAuth := playerAuthMiddleware(false)
+	group.GET("/player/:id", playerAuth, getPlayerHandler)
+	group.GET("/player/:id/surroundings", playerAuth, getSurroundingsHandler)
+	group.GET("/player/:id/view", playerAuth, getViewHandler)
+	group.GET("/player/:id/combat-preview", playerAuth, getCombatPreviewHandler)
+	group.GET("/player/:id/stream", playerAuthMiddleware(true), streamPlayerHandler)
+	group.PUT("/player/:id/direction/:dir", playerAuth, setDirectionHandler)
+	group.PUT("/player/:id/play/:cardType", playerAuth, setPlayHandler)
+	group.PUT("/player/:id/discard/:cardType", playerAuth, setDiscardHandler)
+	group.GET("/player/:id/trades", playerAuth, getTradesHandler)
+	group.PUT("/player/:id/trades/:other", playerAuth, offerTradeHandler)
+	group.PUT("/player/:id/trades/:other/accept", playerAuth, acceptTradeHandler)
+	group.DELETE("/player/:id/trades/:other", playerAuth, cancelTradeHandler)
+	group.PUT("/player/:id/ready", playerAuth, setReadyHandler(true))
+	group.DELETE("/player/:id/ready", playerAuth, setReadyHandler(false))
 
 	// Event log endpoints
-	router.GET("/player/:id/events", getPlayerEventsHandler)
-	router.GET("/player/:id/events/type/:eventType", getPlayerEventsByTypeHandler)
+	group.GET("/player/:id/events", playerAuth, getPlayerEventsHandler)
+	group.GET("/player/:id/events/type/:eventType", playerAuth, getPlayerEventsByTypeHandler)
+
+	// Admin endpoints
+	registerAdminRoutes(group.Group("/admin", adminAuthMiddleware()))
+}
+
+// gameMiddleware looks up the game addressed by the :gameId parameter,
+// falling back to the default game, and stores it in the context
+func gameMiddleware(registry *GameRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		gameID := c.Param("gameId")
+		if gameID == "" {
+			gameID = defaultGameID
+		}
+		game := registry.get(gameID)
+		if game == nil {
+			sendErrorResponse(c, http.StatusNotFound, "game_not_found", "Game not found")
+			c.Abort()
+			return
+		}
+		c.Set(gameContextKey, game)
+		c.Next()
+	}
+}
 
-	router.Run("0.0.0.0:8080")
+const gameContextKey = "game"
+
+// currentGame returns the game stored by gameMiddleware
+func currentGame(c *gin.Context) *Game {
+	return c.MustGet(gameContextKey).(*Game)
+}
+
+func listGamesHandler(registry *GameRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		games := registry.list()
+		summaries := make([]GameSummary, 0, len(games))
+		for _, game := range games {
+			summaries = append(summaries, game.summary())
+		}
+		sendSuccessResponse(c, http.StatusOK, gin.H{
+			"games": summaries,
+			"count": len(summaries),
+		})
+	}
+}
+
+// createGameHandler starts a new game. The optional JSON body overrides the
+// gameplay fields of the server's base config, see Config.mergeClient.
+func createGameHandler(registry *GameRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		config := gameConfig.clone()
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, clientMaxConfigBytes))
+		if err != nil {
+			sendErrorResponse(c, http.StatusBadRequest, "invalid_body", err.Error())
+			return
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := config.mergeClient(body); err != nil {
+				sendErrorResponse(c, http.StatusBadRequest, "invalid_config", err.Error())
+				return
+			}
+		}
+		if err := config.Validate(); err != nil {
+			sendErrorResponse(c, http.StatusBadRequest, "invalid_config", err.Error())
+			return
+		}
+
+		game, err := registry.create("", config)
+		if errors.Is(err, errGameExists) {
+			sendErrorResponse(c, http.StatusConflict, "game_exists", err.Error())
+			return
+		} else if err != nil {
+			sendErrorResponse(c, http.StatusBadRequest, "invalid_config", err.Error())
+			return
+		}
+		go game.run()
+		sendSuccessResponse(c, http.StatusCreated, gin.H{
+			"game": game.summary(),
+		})
+	}
 }
 
 func getAllConfigHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, gState)
+	game := currentGame(c)
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+	c.JSON(http.StatusOK, ConfigResponse{
+		TurnLength:     game.config.Game.TurnLength,
+		TurnTime:       game.state.turnTimer,
+		RemainingTurns: game.state.getRemainingTurns(),
+		HaveWon:        game.state.haveWon(),
+		Seed:           game.state.getSeed(),
+		MapSeed:        game.config.Map.Seed,
+	})
 }
 
 func addPlayerHandler(c *gin.Context) {
-	var pId = pMap.addPlayer(
-		filterPlayerName(c.Param("name")),
-		gMap.getNewPlayerEntryTile())
-	c.JSON(http.StatusOK, pId)
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	var pId = game.pMap.addPlayer(
+		filterPlayerName(c.Param("name"), game.config),
+		game.gMap.getNewPlayerEntryTile())
+	// The ID is public, the token is the player's credential and only returned here
+	c.JSON(http.StatusOK, NewPlayerResponse{ID: pId, Token: game.playerToken(pId)})
 }
 
 func getSurroundingsHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.RLock()
+	defer game.mu.RUnlock()
 	id := c.Param("id")
-	var player = pMap.getPlayer(id)
-
-	// Special case: if player is dead, return laboratory surrounded by edges
-	if !player.Alive {
-		deadPlayerSurroundings := Surroundings{
-			NW: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
-			NN: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
-			NE: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
-			WW: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
-			CE: MapPiece{Laboratory.toString(), 0, 0, 0, 0, 0, 0},
-			EE: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
-			SW: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
-			SS: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
-			SE: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
-		}
-		c.JSON(http.StatusOK, deadPlayerSurroundings)
-		return
-	}
+	var player = game.pMap.getPlayer(id)
+	c.JSON(http.StatusOK, game.playerSurroundings(player))
+}
 
-	var xPos = player.CurrentTile.XPos
-	var yPos = player.CurrentTile.YPos
-	c.JSON(http.StatusOK, gMap.getSurroundingsFromPos(xPos, yPos))
-	return
+// getViewHandler returns the grid of tiles around the player, see View. The fixed
+// 3x3 surroundings endpoint stays for older clients.
+func getViewHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+	c.JSON(http.StatusOK, game.playerView(game.getPlayerOrNil(c.Param("id"))))
 }
 
 func setPlayHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
 	id := c.Param("id")
 	cardStr := c.Param("cardType")
-	playerPtr := getPlayerOrNil(id)
+	playerPtr := game.getPlayerOrNil(id)
 	if playerPtr != nil {
 		playerPtr.cardInput(cardStr)
 		c.Status(http.StatusOK)
@@ -89,11 +208,31 @@ func setPlayHandler(c *gin.Context) {
 	}
 }
 
+// setDiscardHandler chooses the card the player discards if its hand is over
+// the limit at the end of the tick. "none" discards the newest card instead.
+func setDiscardHandler(c *gin.Context) {
+	card, exists := cards[strings.ToLower(c.Param("cardType"))]
+	if !exists {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_card", "Unknown card type: "+c.Param("cardType"))
+		return
+	}
+
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	player := game.getPlayerOrNil(c.Param("id"))
+	player.selectDiscard(card)
+	sendSuccessResponse(c, http.StatusOK, gin.H{"discard": card})
+}
+
 func setDirectionHandler(c *gin.Context) {
 	id := c.Param("id")
 	dirStr := c.Param("dir")
 	var dir = directions[strings.ToLower(dirStr)]
-	playerPtr := getPlayerOrNil(id)
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	playerPtr := game.getPlayerOrNil(id)
 	if playerPtr != nil {
 		(*playerPtr).Direction = dir
 		c.Status(http.StatusOK)
@@ -102,9 +241,38 @@ func setDirectionHandler(c *gin.Context) {
 	}
 }
 
+// setReadyHandler marks the player as (not) ready. The turn ends early once all
+// living human players are ready.
+func setReadyHandler(ready bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		game := currentGame(c)
+		game.mu.Lock()
+		playerPtr := game.getPlayerOrNil(c.Param("id"))
+		if playerPtr == nil {
+			game.mu.Unlock()
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		playerPtr.Ready = ready
+		waiting, _ := game.pMap.playersNotReady()
+		game.mu.Unlock()
+
+		if ready {
+			game.wakeLoop()
+		}
+		sendSuccessResponse(c, http.StatusOK, gin.H{
+			"ready":       ready,
+			"waiting_for": waiting,
+		})
+	}
+}
+
 func getPlayerHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.RLock()
+	defer game.mu.RUnlock()
 	id := c.Param("id")
-	playerPtr := getPlayerOrNil(id)
+	playerPtr := game.getPlayerOrNil(id)
 	if playerPtr != nil {
 		c.JSON(http.StatusOK, (*playerPtr))
 		return
@@ -113,9 +281,9 @@ func getPlayerHandler(c *gin.Context) {
 	}
 }
 
-func filterPlayerName(name string) string {
-	if len(name) > gameConfig.Player.NameMaxLength {
-		return name[0:gameConfig.Player.NameMaxLength]
+func filterPlayerName(name string, config *Config) string {
+	if len(name) > config.Player.NameMaxLength {
+		return name[0:config.Player.NameMaxLength]
 	}
 	//TODO: Filter bad words
 	return name
@@ -123,16 +291,19 @@ func filterPlayerName(name string) string {
 
 // getPlayerEventsHandler returns a handler for getting recent events for a player
 func getPlayerEventsHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.RLock()
+	defer game.mu.RUnlock()
 	playerID := c.Param("id")
 
 	// Verify player exists
-	if playerID != "" && pMap.getPlayerPtr(playerID) == nil {
+	if playerID != "" && game.pMap.getPlayerPtr(playerID) == nil {
 		sendErrorResponse(c, http.StatusNotFound, "player_not_found", "Player not found")
 		return
 	}
 
 	// Parse query parameters
-	lastTurns := 5 // Default to last 5 turns
+	lastTurns := game.config.Api.DefaultReportedTurns
 	if turnsStr := c.Query("turns"); turnsStr != "" {
 		if turns, err := strconv.Atoi(turnsStr); err == nil && turns > 0 {
 			lastTurns = turns
@@ -144,7 +315,7 @@ func getPlayerEventsHandler(c *gin.Context) {
 		LastTurns: lastTurns,
 	}
 
-	events := eventLogger.GetPlayerEvents(playerID, filters)
+	events := game.events.GetPlayerEvents(playerID, filters)
 
 	sendSuccessResponse(c, http.StatusOK, gin.H{
 		"events": events,
@@ -154,11 +325,14 @@ func getPlayerEventsHandler(c *gin.Context) {
 
 // getPlayerEventsByTypeHandler returns a handler for getting filtered events for a player
 func getPlayerEventsByTypeHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.RLock()
+	defer game.mu.RUnlock()
 	playerID := c.Param("id")
 	eventType := EventType(c.Param("eventType"))
 
 	// Verify player exists
-	if playerID != "" && pMap.getPlayerPtr(playerID) == nil {
+	if playerID != "" && game.pMap.getPlayerPtr(playerID) == nil {
 		sendErrorResponse(c, http.StatusNotFound, "player_not_found", "Player not found")
 		return
 	}
@@ -177,7 +351,7 @@ func getPlayerEventsByTypeHandler(c *gin.Context) {
 		return
 	}
 
-	lastTurns := gameConfig.Api.DefaultReportedTurns
+	lastTurns := game.config.Api.DefaultReportedTurns
 	if turnsStr := c.Query("turns"); turnsStr != "" {
 		if turns, err := strconv.Atoi(turnsStr); err == nil && turns > 0 {
 			lastTurns = turns
@@ -185,7 +359,7 @@ func getPlayerEventsByTypeHandler(c *gin.Context) {
 	}
 
 	// Get filtered events
-	events := eventLogger.GetPlayerEvents(playerID, EventFilters{
+	events := game.events.GetPlayerEvents(playerID, EventFilters{
 		EventType: eventType,
 		LastTurns: lastTurns,
 	})
@@ -197,6 +371,16 @@ func getPlayerEventsByTypeHandler(c *gin.Context) {
 	})
 }
 
+// getSnapshotHandler downloads the current snapshot of the game
+func getSnapshotHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", game.ID+".json"))
+	c.JSON(http.StatusOK, game.snapshot())
+}
+
 // errorHandlingMiddleware provides centralized error handling and logging
 func errorHandlingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// APITestSuite serves the router of a registry holding a default game
+type APITestSuite struct {
+	t        *testing.T
+	registry *GameRegistry
+	router   *gin.Engine
+}
+
+func setupAPITestSuite(t *testing.T) *APITestSuite {
+	gin.SetMode(gin.TestMode)
+	registry := NewGameRegistry()
+	config := gameConfig.clone()
+	config.Game.Seed = 1
+	_, err := registry.create(defaultGameID, config)
+	require.NoError(t, err)
+	return &APITestSuite{t: t, registry: registry, router: newRouter(registry)}
+}
+
+// request performs an HTTP request against the router and returns the recorder
+func (as *APITestSuite) request(method, path, body string) *httptest.ResponseRecorder {
+	return as.requestWithToken(method, path, body, "")
+}
+
+// requestWithToken performs an HTTP request with a bearer token
+func (as *APITestSuite) requestWithToken(method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	as.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// addPlayer joins a player through the API at the given path prefix ("" for the default game)
+func (as *APITestSuite) addPlayer(prefix, name string) NewPlayerResponse {
+	rec := as.request(http.MethodPost, prefix+"/player/"+name, "")
+	require.Equal(as.t, http.StatusOK, rec.Code, rec.Body.String())
+	var player NewPlayerResponse
+	as.decode(rec, &player)
+	return player
+}
+
+// decode unmarshals the JSON body of a response
+func (as *APITestSuite) decode(rec *httptest.ResponseRecorder, target interface{}) {
+	require.NoError(as.t, json.Unmarshal(rec.Body.Bytes(), target), rec.Body.String())
+}
+
+func TestGameLobbies(t *testing.T) {
+	t.Run("create game with config overrides", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+
+		rec := as.request(http.MethodPost, "/games", `{"Map": {"Width": 5, "Height": 6}, "Game": {"TurnLength": 30}}`)
+
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var response struct{ Game GameSummary }
+		as.decode(rec, &response)
+		game := as.registry.get(response.Game.ID)
+		require.NotNil(t, game, "Created game should be registered")
+		assert.Equal(t, 5, game.gMap.width)
+		assert.Equal(t, 6, game.gMap.height)
+		assert.Equal(t, 30, response.Game.TurnLength)
+		assert.Equal(t, gameConfig.Map.Width, as.registry.get(defaultGameID).gMap.width,
+			"Default game should keep the base config")
+	})
+
+	t.Run("invalid config is rejected", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+
+		rec := as.request(http.MethodPost, "/games", `{"Map": {"Width": -1}}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Map.Width")
+		assert.Len(t, as.registry.list(), 1, "No game should be created")
+	})
+
+	t.Run("server settings and map files are not accepted from clients", func(t *testing.T) {
+		for _, body := range []string{
+			`{"Server": {"AdminToken": "mine"}}`,
+			`{"eventlog": {"Backend": "file", "Directory": "/tmp/elsewhere"}}`,
+			`{"Snapshot": {"Directory": "/tmp/elsewhere"}}`,
+			`{"Map": {"file": "/etc/passwd"}}`,
+			`{"Map": {"Width": 100000, "Height": 100000}}`,
+			`{"Player": {"VisionRadius": 3000}}`,
+			`{"Map": {"MinLabDistance": 500}}`,
+			`{"Map": {"TerrainWeights": {"Laboratory": 100, "Forest": 1}}}`,
+			`{"Game": {"TurnLength": 1}}`,
+			`{"Game": {"MaxTurns": 1000000}}`,
+			`{"TerrainResources": {"Forest": {"Amount": 1000000000, "GivesCard": "Wood"}}}`,
+		} {
+			as := setupAPITestSuite(t)
+
+			rec := as.request(http.MethodPost, "/games", body)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+			assert.Contains(t, rec.Body.String(), "invalid_config", body)
+			assert.Len(t, as.registry.list(), 1, "No game should be created")
+		}
+
+		as := setupAPITestSuite(t)
+		huge := `{"Game": {"BotStrategies": ["` + strings.Repeat("x", clientMaxConfigBytes) + `"]}}`
+		assert.Equal(t, http.StatusBadRequest, as.request(http.MethodPost, "/games", huge).Code)
+	})
+
+	t.Run("list games", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		as.request(http.MethodPost, "/games", "")
+
+		rec := as.request(http.MethodGet, "/games", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			Games []GameSummary
+			Count int
+		}
+		as.decode(rec, &response)
+		assert.Equal(t, 2, response.Count)
+	})
+
+	t.Run("players are scoped to their game", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		game, err := as.registry.create("second", gameConfig.clone())
+		require.NoError(t, err)
+
+		player := as.addPlayer("/games/second", "Alice")
+
+		assert.NotNil(t, game.getPlayerOrNil(player.ID), "Player should join the addressed game")
+		assert.Equal(t, http.StatusOK,
+			as.requestWithToken(http.MethodGet, "/games/second/player/"+player.ID, "", player.Token).Code)
+		assert.Equal(t, http.StatusForbidden,
+			as.requestWithToken(http.MethodGet, "/player/"+player.ID, "", player.Token).Code,
+			"Player should not exist in the default game")
+	})
+
+	t.Run("game IDs being built are reserved", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		as.registry.pending["second"] = true
+
+		_, err := as.registry.create("second", gameConfig.clone())
+		assert.ErrorIs(t, err, errGameExists)
+		_, err = as.registry.create(defaultGameID, gameConfig.clone())
+		assert.ErrorIs(t, err, errGameExists)
+
+		delete(as.registry.pending, "second")
+		_, err = as.registry.create("second", gameConfig.clone())
+		assert.NoError(t, err)
+	})
+
+	t.Run("legacy routes address the default game", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+
+		rec := as.request(http.MethodPost, "/player/Bob", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, as.registry.get(defaultGameID).pMap.Players, 1)
+	})
+
+	t.Run("unknown game returns not found", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+
+		rec := as.request(http.MethodGet, "/games/missing/config", "")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "game_not_found")
+	})
+}
+
+func TestConcurrentAPIAndTicks(t *testing.T) {
+	t.Run("handlers and ticks do not race", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		game := as.registry.get(defaultGameID)
+		var players []NewPlayerResponse
+		for i := 0; i < 5; i++ {
+			players = append(players, as.addPlayer("", "Loader"))
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 20; i++ {
+				game.endTurn()
+			}
+		}()
+
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					player := players[(worker+i)%len(players)]
+					path := "/player/" + player.ID
+					as.requestWithToken(http.MethodPut, path+"/direction/"+Directions[i%len(Directions)].toString(), "", player.Token)
+					as.requestWithToken(http.MethodPut, path+"/play/weapon", "", player.Token)
+					as.requestWithToken(http.MethodGet, path, "", player.Token)
+					as.requestWithToken(http.MethodGet, path+"/surroundings", "", player.Token)
+					as.requestWithToken(http.MethodGet, path+"/events", "", player.Token)
+					as.request(http.MethodGet, "/config", "")
+					if i%10 == 0 {
+						as.request(http.MethodPost, "/player/Joiner", "")
+						as.request(http.MethodGet, "/games", "")
+					}
+				}
+			}(worker)
+		}
+		wg.Wait()
+		<-done
+
+		assert.Len(t, game.pMap.Players, 5+8*5, "Every join should be applied")
+	})
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newIDSalt returns a random salt for games configured without one
+func newIDSalt() string {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(salt)
+}
+
+// playerToken derives the secret token of a player. Player IDs are public and
+// shown to other players; the token is an HMAC of game and player ID keyed with
+// the game's ID salt, so it cannot be guessed from the ID.
+func (g *Game) playerToken(playerID string) string {
+	mac := hmac.New(sha256.New, []byte(g.config.Server.IDSalt))
+	mac.Write([]byte(g.ID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(playerID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// playerAuthMiddleware guards the routes of the player in the :id parameter.
+// Requests must carry the player's token as "Authorization: Bearer <token>".
+// With allowQueryToken the token may also be given as ?token=, for clients such
+// as browser EventSource that cannot set headers.
+func playerAuthMiddleware(allowQueryToken bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		game := currentGame(c)
+		playerID := c.Param("id")
+
+		game.mu.RLock()
+		exists := game.getPlayerOrNil(playerID) != nil
+		game.mu.RUnlock()
+		if !exists {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		token := bearerToken(c)
+		if token == "" && allowQueryToken {
+			token = c.Query("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(game.playerToken(playerID))) != 1 {
+			sendErrorResponse(c, http.StatusUnauthorized, "unauthorized", "Missing or invalid player token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestLogFormatter formats the request log like gin's default logger, but
+// with the value of a ?token= query parameter redacted so that player tokens
+// never end up in the logs
+func requestLogFormatter(param gin.LogFormatterParams) string {
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		redactToken(param.Path),
+		param.ErrorMessage,
+	)
+}
+
+// redactToken replaces the token query parameter of a logged path
+func redactToken(path string) string {
+	base, rawQuery, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return base + "?REDACTED"
+	}
+	if !query.Has("token") {
+		return path
+	}
+	query.Set("token", "REDACTED")
+	return base + "?" + query.Encode()
+}
+
+// adminAuthMiddleware requires the game's admin token as a bearer token.
+// Games without an admin token have their admin endpoints disabled.
+func adminAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := currentGame(c).config.Server.AdminToken
+		if token == "" {
+			sendErrorResponse(c, http.StatusForbidden, "admin_disabled", "Admin endpoints are disabled for this game")
+			c.Abort()
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(bearerToken(c)), []byte(token)) != 1 {
+			sendErrorResponse(c, http.StatusUnauthorized, "unauthorized", "Invalid admin token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// bearerToken returns the token of an "Authorization: Bearer <token>" header
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if len(header) > len("Bearer ") && strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(header[len("Bearer "):])
+	}
+	return ""
+}
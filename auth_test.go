@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerTokens(t *testing.T) {
+	t.Run("join returns a token distinct from the public ID", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+
+		player := as.addPlayer("", "Alice")
+
+		assert.NotEmpty(t, player.Token)
+		assert.NotEqual(t, player.ID, player.Token)
+		assert.NotContains(t, player.Token, player.ID)
+	})
+
+	t.Run("private and mutating endpoints require the token", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		player := as.addPlayer("", "Alice")
+		other := as.addPlayer("", "Mallory")
+		path := "/player/" + player.ID
+
+		endpoints := []struct {
+			method string
+			path   string
+		}{
+			{http.MethodGet, path},
+			{http.MethodGet, path + "/surroundings"},
+			{http.MethodGet, path + "/events"},
+			{http.MethodGet, path + "/events/type/player_move"},
+			{http.MethodPut, path + "/direction/north"},
+			{http.MethodPut, path + "/play/weapon"},
+		}
+		for _, endpoint := range endpoints {
+			assert.Equal(t, http.StatusUnauthorized,
+				as.request(endpoint.method, endpoint.path, "").Code,
+				"%s %s without token", endpoint.method, endpoint.path)
+			assert.Equal(t, http.StatusUnauthorized,
+				as.requestWithToken(endpoint.method, endpoint.path, "", other.Token).Code,
+				"%s %s with another player's token", endpoint.method, endpoint.path)
+			assert.Equal(t, http.StatusOK,
+				as.requestWithToken(endpoint.method, endpoint.path, "", player.Token).Code,
+				"%s %s with the player's token", endpoint.method, endpoint.path)
+		}
+	})
+
+	t.Run("rejected requests do not change the player", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		player := as.addPlayer("", "Alice")
+		game := as.registry.get(defaultGameID)
+		before := game.getPlayerOrNil(player.ID).Direction
+
+		as.request(http.MethodPut, "/player/"+player.ID+"/direction/stay", "")
+
+		assert.Equal(t, before, game.getPlayerOrNil(player.ID).Direction)
+	})
+
+	t.Run("tokens differ between games with the same salt", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Server.IDSalt = "salt"
+		first, _ := NewGame("first", config)
+		second, _ := NewGame("second", config)
+
+		assert.NotEqual(t, first.playerToken("player"), second.playerToken("player"))
+	})
+
+	t.Run("games without a salt get a random one", func(t *testing.T) {
+		first, _ := NewGame("game", gameConfig.clone())
+		second, _ := NewGame("game", gameConfig.clone())
+
+		assert.NotEmpty(t, first.config.Server.IDSalt)
+		assert.NotEqual(t, first.playerToken("player"), second.playerToken("player"))
+	})
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BotOrders are the orders a bot gives for the coming tick
+type BotOrders struct {
+	Direction Direction
+	Play      Card
+	Consume   Card
+	Discard   Card
+}
+
+// apply sets the orders on the bot like the API would for a human
+func (o BotOrders) apply(p *Player) {
+	p.Direction = o.Direction
+	p.Play = o.Play
+	p.Consume = o.Consume
+	p.Discard = o.Discard
+}
+
+// BotView is everything a bot may base its orders on. It is the same
+// information a human player gets from the API.
+type BotView struct {
+	Player       Player
+	Surroundings Surroundings
+	Events       []GameEvent // the player's events of the last turns, oldest first
+	Config       *Config
+}
+
+// BotStrategy decides the orders of a bot. Strategies must draw all randomness
+// from the given RNG so that seeded games stay reproducible.
+type BotStrategy interface {
+	Decide(view BotView, rng *RNG) BotOrders
+}
+
+// Names of the built-in bot strategies
+const (
+	BotStrategyBaseline = "baseline"
+	BotStrategyRandom   = "random"
+	BotStrategyCautious = "cautious"
+	BotStrategyResearch = "research"
+	BotStrategyHerd     = "herd"
+)
+
+// botStrategies is the registry of strategies selectable through Game.BotStrategies
+var botStrategies = map[string]BotStrategy{
+	BotStrategyBaseline: baselineStrategy,
+	BotStrategyRandom:   randomWalker{},
+	BotStrategyCautious: heuristicStrategy{
+		zombieWeight: 5, cutoffPenalty: 100, cutoffMargin: 1,
+		farmBonus: 8, forestBonus: 6, cityBonus: 1, labBonus: 3, winBonus: 1000,
+	},
+	BotStrategyResearch: heuristicStrategy{
+		zombieWeight: 1, cutoffPenalty: 50,
+		farmBonus: 4, forestBonus: 2, cityBonus: 2, labBonus: 12, winBonus: 1000,
+	},
+	BotStrategyHerd: heuristicStrategy{
+		zombieWeight: 2, cutoffPenalty: 100,
+		farmBonus: 4, forestBonus: 3, cityBonus: 2, labBonus: 3, winBonus: 1000, herdBonus: 5,
+	},
+}
+
+// BotStrategyNames returns the names of all registered strategies in sorted order
+func BotStrategyNames() []string {
+	names := make([]string, 0, len(botStrategies))
+	for name := range botStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// botStrategyFor returns the strategy of the n-th bot, counting from 0. The
+// configured strategies are assigned round robin, no configuration means baseline.
+func botStrategyFor(config *Config, n int) string {
+	if len(config.Game.BotStrategies) == 0 {
+		return BotStrategyBaseline
+	}
+	return config.Game.BotStrategies[n%len(config.Game.BotStrategies)]
+}
+
+// heuristicStrategy scores each reachable tile and walks to the best one.
+// The built-in play styles only differ in their weights.
+type heuristicStrategy struct {
+	zombieWeight  float64 // penalty per zombie on the target
+	cutoffPenalty float64 // penalty for targets above ZombieCutoff - cutoffMargin
+	cutoffMargin  int
+	farmBonus     float64 // for a farm while out of food
+	forestBonus   float64 // for a forest while short on wood
+	cityBonus     float64 // for a city while unarmed
+	labBonus      float64 // for a laboratory while short on research
+	winBonus      float64 // for a laboratory the bot can win at
+	herdBonus     float64 // per player on the target
+}
+
+// baselineStrategy avoids tiles with more zombies than ZombieCutoff, walks towards
+// the food and wood it is missing and heads into a Laboratory once it carries
+// enough Research.
+var baselineStrategy = heuristicStrategy{
+	zombieWeight: 2, cutoffPenalty: 100,
+	farmBonus: 8, forestBonus: 6, cityBonus: 4, labBonus: 3, winBonus: 1000,
+}
+
+func (h heuristicStrategy) Decide(view BotView, rng *RNG) BotOrders {
+	player := view.Player
+	orders := defaultBotOrders(player)
+
+	bestScore, found := 0.0, false
+	for _, direction := range Directions {
+		piece := view.Surroundings.inDirection(direction)
+		if piece.terrain() == Edge {
+			continue
+		}
+		x, y := calculateNewPosition(player.CurrentTile.XPos, player.CurrentTile.YPos, direction)
+		// The random part only breaks ties, drawn from the game RNG to stay reproducible
+		score := h.score(player, piece, x, y, view.Config) + rng.Float64()*0.1
+		if !found || score > bestScore {
+			bestScore, found = score, true
+			orders.Direction = direction
+		}
+	}
+
+	orders.Play = playWeaponAgainst(player, view.Surroundings.inDirection(orders.Direction), view.Config)
+	return orders
+}
+
+// score rates moving onto piece at x, y; higher is better
+func (h heuristicStrategy) score(player Player, piece MapPiece, x, y int, config *Config) float64 {
+	score := -h.zombieWeight * float64(piece.ZombieCount)
+	if piece.ZombieCount > config.Combat.ZombieCutoff-h.cutoffMargin {
+		score -= h.cutoffPenalty
+	}
+	score += h.herdBonus * float64(piece.PlayerCount)
+
+	switch piece.terrain() {
+	case Laboratory:
+		if player.usableResearchAt(x, y) >= config.Game.VictoryNumber {
+			score += h.winBonus
+		} else {
+			score += h.labBonus
+		}
+	case Farm:
+		if player.countCards(Food) == 0 {
+			score += h.farmBonus
+		} else {
+			score++
+		}
+	case Forest:
+		if player.countCards(Wood) < 2 {
+			score += h.forestBonus
+		} else {
+			score++
+		}
+	case City:
+		if player.countCards(Weapon) == 0 {
+			score += h.cityBonus
+		}
+	}
+	return score
+}
+
+// randomWalker moves in a random direction that stays on the map
+type randomWalker struct{}
+
+func (randomWalker) Decide(view BotView, rng *RNG) BotOrders {
+	orders := defaultBotOrders(view.Player)
+	var options []Direction
+	for _, direction := range Directions {
+		if view.Surroundings.inDirection(direction).terrain() != Edge {
+			options = append(options, direction)
+		}
+	}
+	if len(options) > 0 {
+		orders.Direction = options[rng.Intn(len(options))]
+	}
+	orders.Play = playWeaponAgainst(view.Player, view.Surroundings.inDirection(orders.Direction), view.Config)
+	return orders
+}
+
+// defaultBotOrders stays put, eats food before wood and discards surplus resources
+func defaultBotOrders(player Player) BotOrders {
+	orders := BotOrders{Direction: Stay, Play: None, Consume: Wood, Discard: None}
+	if player.countCards(Food) > 0 {
+		orders.Consume = Food
+	}
+	switch {
+	case player.countCards(Wood) > 2:
+		orders.Discard = Wood
+	case player.countCards(Food) > 2:
+		orders.Discard = Food
+	}
+	return orders
+}
+
+// playWeaponAgainst plays a weapon, or without one a Dice card, if the target has
+// at least as many zombies as an average roll
+func playWeaponAgainst(player Player, target MapPiece, config *Config) Card {
+	averageRoll := (config.Combat.PlayerMinAttack + config.Combat.PlayerMaxAttack) / 2
+	if target.ZombieCount < averageRoll {
+		return None
+	}
+	switch {
+	case player.countCards(Weapon) > 0:
+		return Weapon
+	case player.countCards(Dice) > 0:
+		return Dice
+	}
+	return None
+}
+
+// botName names the n-th bot of a game, counting from 1
+func botName(n int) string {
+	return fmt.Sprintf("Bot %d", n)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBots(t *testing.T) {
+	// setupBotAt surrounds a bot at x, y with empty forest and gives it a hand that lacks nothing
+	setupBotAt := func(ts *TestSuite, x, y int) *Player {
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				ts.setupTile(x+dx, y+dy, Forest, 0)
+			}
+		}
+		bot := ts.getPlayer(ts.playerMap.addBot("Bot", BotStrategyBaseline, ts.gameMap.getTileFromPos(x, y)))
+		bot.setHand([]Card{Food, Wood, Wood, Weapon})
+		return bot
+	}
+	orders := func(ts *TestSuite, bot *Player) BotOrders {
+		view := BotView{Player: *bot, Surroundings: ts.game.playerSurroundings(*bot), Config: ts.game.config}
+		return botStrategies[bot.Strategy].Decide(view, ts.game.rng)
+	}
+
+	t.Run("configured number of bots spawn at game start", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Game.Seed = 1
+		config.Game.BotNumber = 3
+
+		game, err := NewGame("bots", config)
+		require.NoError(t, err)
+
+		require.Len(t, game.pMap.Players, 3)
+		for _, player := range game.pMap.Players {
+			assert.True(t, player.IsBot)
+			assert.True(t, player.Alive)
+			assert.NotNil(t, player.CurrentTile)
+		}
+	})
+
+	t.Run("bot avoids tiles above the zombie cutoff", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		bot := setupBotAt(ts, 2, 2)
+		bot.setHand([]Card{Food, Wood, Wood})
+		ts.setupTile(2, 1, Farm, ts.game.config.Combat.ZombieCutoff+1)
+		ts.setupTile(3, 2, Farm, ts.game.config.Combat.ZombieCutoff+1)
+		ts.setupTile(2, 3, Farm, ts.game.config.Combat.ZombieCutoff+1)
+		ts.setupTile(1, 2, Farm, ts.game.config.Combat.ZombieCutoff+1)
+
+		assert.Equal(t, Stay, orders(ts, bot).Direction)
+	})
+
+	t.Run("bot on the north edge picks the least bad move", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		bot := setupBotAt(ts, 2, 0)
+		ts.setupTile(2, 0, Forest, 3)
+		ts.setupTile(1, 0, Forest, 3)
+		ts.setupTile(2, 1, Forest, 3)
+		ts.setupTile(3, 0, Forest, 1)
+
+		assert.Equal(t, East, orders(ts, bot).Direction)
+	})
+
+	t.Run("bot without food seeks a farm", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		bot := setupBotAt(ts, 2, 2)
+		bot.setHand([]Card{Wood, Wood})
+		ts.setupTile(3, 2, Farm, 0)
+
+		o := orders(ts, bot)
+
+		assert.Equal(t, East, o.Direction)
+		assert.Equal(t, Wood, o.Consume, "Bot without food should burn wood")
+	})
+
+	t.Run("bot with enough research heads to a laboratory", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		bot := setupBotAt(ts, 2, 2)
+		ts.setupTile(2, 1, Laboratory, 0)
+		ts.setupTile(2, 3, Farm, 0)
+		for i := 0; i < ts.game.config.Game.VictoryNumber; i++ {
+			bot.Cards[i] = Research
+			bot.ResearchAcquisitionPos[i] = [2]int{0, 0}
+		}
+
+		assert.Equal(t, North, orders(ts, bot).Direction)
+	})
+
+	t.Run("bots play through several ticks", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Game.Seed = 7
+		config.Game.BotNumber = 4
+		game, err := NewGame("bots", config)
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			game.tick()
+		}
+
+		assert.Len(t, game.pMap.Players, 4)
+	})
+
+	t.Run("research rusher prefers a laboratory over food", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		bot := setupBotAt(ts, 2, 2)
+		bot.Strategy = BotStrategyResearch
+		bot.setHand([]Card{Wood, Wood})
+		ts.setupTile(3, 2, Farm, 0)
+		ts.setupTile(1, 2, Laboratory, 0)
+
+		assert.Equal(t, West, orders(ts, bot).Direction)
+	})
+
+	t.Run("cautious survivor keeps away from tiles near the cutoff", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		bot := setupBotAt(ts, 2, 2)
+		bot.Strategy = BotStrategyCautious
+		bot.setHand([]Card{Wood, Wood})
+		ts.setupTile(3, 2, Farm, ts.game.config.Combat.ZombieCutoff)
+
+		assert.NotEqual(t, East, orders(ts, bot).Direction)
+	})
+
+	t.Run("herd follower joins other players", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		bot := setupBotAt(ts, 2, 2)
+		bot.Strategy = BotStrategyHerd
+		ts.playerMap.addPlayer("Alice", ts.gameMap.getTileFromPos(2, 3))
+		ts.playerMap.addPlayer("Bob", ts.gameMap.getTileFromPos(2, 3))
+
+		assert.Equal(t, South, orders(ts, bot).Direction)
+	})
+
+	t.Run("random walker stays on the map", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		bot := ts.getPlayer(ts.playerMap.addBot("Bot", BotStrategyRandom, ts.gameMap.getTileFromPos(0, 0)))
+
+		for i := 0; i < 20; i++ {
+			direction := orders(ts, bot).Direction
+			assert.NotEqual(t, North, direction)
+			assert.NotEqual(t, West, direction)
+		}
+	})
+
+	t.Run("configured strategies are assigned round robin", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Game.Seed = 1
+		config.Game.BotNumber = 3
+		config.Game.BotStrategies = []string{BotStrategyRandom, BotStrategyHerd}
+
+		game, err := NewGame("bots", config)
+		require.NoError(t, err)
+
+		strategies := map[string]string{}
+		for _, player := range game.pMap.Players {
+			strategies[player.Name] = player.Strategy
+		}
+		assert.Equal(t, map[string]string{
+			botName(1): BotStrategyRandom,
+			botName(2): BotStrategyHerd,
+			botName(3): BotStrategyRandom,
+		}, strategies)
+	})
+
+	t.Run("unknown strategy is a config error", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Game.BotStrategies = []string{"psychic"}
+
+		assert.ErrorContains(t, config.Validate(), "Game.BotStrategies[0]")
+	})
+
+	t.Run("every strategy plays through several ticks", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Game.Seed = 3
+		config.Game.BotStrategies = BotStrategyNames()
+		config.Game.BotNumber = len(config.Game.BotStrategies)
+		game, err := NewGame("bots", config)
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			game.tick()
+		}
+
+		assert.Len(t, game.pMap.Players, config.Game.BotNumber)
+	})
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 type Card int
@@ -21,10 +23,32 @@ func (c Card) String() string {
 	return []string{"Food", "Wood", "Weapon", "Dice", "Research", "None"}[c]
 }
 
+func (c Card) isValid() bool {
+	return c >= Food && c <= None
+}
+
+// isPlayable is true for the cards a player can play in combat
+func (c Card) isPlayable() bool {
+	return c == Weapon || c == Dice
+}
+
 func (c Card) MarshalJSON() ([]byte, error) {
 	return json.Marshal(c.String())
 }
 
+func (c *Card) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	card, exists := cards[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("unknown card %q", name)
+	}
+	*c = card
+	return nil
+}
+
 var cards = map[string]Card{
 	"food":     Food,
 	"wood":     Wood,
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"sort"
+)
+
+// CombatResolver decides the outcome of a fight on a tile. Resolvers must be
+// pure: all randomness comes from the roller and nothing outside the returned
+// outcome is changed, so the same resolver serves real fights and the odds of
+// GET /player/:id/combat-preview.
+type CombatResolver interface {
+	Resolve(input CombatInput, roller CombatRoller) CombatOutcome
+}
+
+// CombatRoller supplies the randomness of a fight
+type CombatRoller interface {
+	// Roll returns an attack roll of the fighter, PlayerMinAttack..PlayerMaxAttack
+	Roll(fighter int) int
+	// Intn returns a number in [0, n) for any other random choice
+	Intn(n int) int
+}
+
+// Fighter is a player taking part in a fight. Play is Weapon or Dice if the
+// player plays that card and holds it, None otherwise.
+type Fighter struct {
+	ID         string
+	Play       Card
+	CanRetreat bool // the player moved onto the tile this tick and can fall back
+}
+
+// CombatInput is everything a resolver needs to know about a fight
+type CombatInput struct {
+	Zombies  int
+	Fighters []Fighter
+	Config   *Config
+}
+
+// CombatOutcome is the result of a fight. The slices are indexed like CombatInput.Fighters.
+type CombatOutcome struct {
+	Strengths     []int
+	Won           bool // the players cleared the tile
+	ZombiesKilled int
+	Killed        []bool
+	Retreated     []bool // escaped a lost fight alive back to the tile it came from
+}
+
+// Names of the built-in combat resolvers
+const (
+	CombatResolverAllOrNothing = "all_or_nothing"
+	CombatResolverAttrition    = "attrition"
+	CombatResolverDuels        = "duels"
+	CombatResolverRetreat      = "retreat"
+)
+
+// combatResolvers is the registry of resolvers selectable through Combat.Resolver
+var combatResolvers = map[string]CombatResolver{
+	CombatResolverAllOrNothing: allOrNothingResolver{},
+	CombatResolverAttrition:    attritionResolver{},
+	CombatResolverDuels:        duelsResolver{},
+	CombatResolverRetreat:      retreatResolver{},
+}
+
+// CombatResolverNames returns the names of all registered resolvers in sorted order
+func CombatResolverNames() []string {
+	names := make([]string, 0, len(combatResolvers))
+	for name := range combatResolvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// combatResolver returns the resolver selected by the game's config
+func (g *Game) combatResolver() CombatResolver {
+	return combatResolvers[g.config.Combat.Resolver]
+}
+
+// strength rolls the strength of the i-th fighter: a weapon has a fixed strength,
+// a Dice card grants a second roll combined according to Combat.DiceEffect
+func (in CombatInput) strength(i int, roller CombatRoller) int {
+	switch in.Fighters[i].Play {
+	case Weapon:
+		return in.Config.Combat.WeaponStrength
+	case Dice:
+		roll := roller.Roll(i)
+		return diceEffects[in.Config.Combat.DiceEffect](roll, roller.Roll(i))
+	}
+	return roller.Roll(i)
+}
+
+// newOutcome rolls the strength of every fighter
+func (in CombatInput) newOutcome(roller CombatRoller) (CombatOutcome, int) {
+	outcome := CombatOutcome{
+		Strengths: make([]int, len(in.Fighters)),
+		Killed:    make([]bool, len(in.Fighters)),
+		Retreated: make([]bool, len(in.Fighters)),
+	}
+	total := 0
+	for i := range in.Fighters {
+		outcome.Strengths[i] = in.strength(i, roller)
+		total += outcome.Strengths[i]
+	}
+	return outcome, total
+}
+
+// allOrNothingResolver is the classic rule: if the players are stronger than
+// the zombies every zombie dies, otherwise every player dies
+type allOrNothingResolver struct{}
+
+func (allOrNothingResolver) Resolve(in CombatInput, roller CombatRoller) CombatOutcome {
+	outcome, total := in.newOutcome(roller)
+	outcome.Won = total > in.Zombies
+	if outcome.Won {
+		outcome.ZombiesKilled = in.Zombies
+	} else {
+		for i := range outcome.Killed {
+			outcome.Killed[i] = true
+		}
+	}
+	return outcome
+}
+
+// attritionResolver lets every point of strength kill one zombie. Each zombie
+// left over kills one player, the weakest first.
+type attritionResolver struct{}
+
+func (attritionResolver) Resolve(in CombatInput, roller CombatRoller) CombatOutcome {
+	outcome, total := in.newOutcome(roller)
+	outcome.ZombiesKilled = min(total, in.Zombies)
+	outcome.Won = outcome.ZombiesKilled == in.Zombies
+
+	weakestFirst := make([]int, len(in.Fighters))
+	for i := range weakestFirst {
+		weakestFirst[i] = i
+	}
+	sort.SliceStable(weakestFirst, func(a, b int) bool {
+		return outcome.Strengths[weakestFirst[a]] < outcome.Strengths[weakestFirst[b]]
+	})
+	leftover := in.Zombies - outcome.ZombiesKilled
+	for _, i := range weakestFirst[:min(leftover, len(weakestFirst))] {
+		outcome.Killed[i] = true
+	}
+	return outcome
+}
+
+// duelsResolver splits the zombies evenly among the players, the first players
+// facing one more if they do not divide evenly. A player who is stronger than
+// its zombies kills them, otherwise it dies and its zombies remain.
+type duelsResolver struct{}
+
+func (duelsResolver) Resolve(in CombatInput, roller CombatRoller) CombatOutcome {
+	outcome, _ := in.newOutcome(roller)
+	outcome.Won = true
+	fighters := len(in.Fighters)
+	for i := range in.Fighters {
+		opponents := in.Zombies / fighters
+		if i < in.Zombies%fighters {
+			opponents++
+		}
+		if outcome.Strengths[i] > opponents {
+			outcome.ZombiesKilled += opponents
+		} else {
+			outcome.Killed[i] = true
+			outcome.Won = false
+		}
+	}
+	return outcome
+}
+
+// retreatResolver is the classic rule, except that after a lost fight every
+// player who moved onto the tile this tick falls back alive to the tile it came
+// from with a chance of Combat.RetreatChance percent
+type retreatResolver struct{}
+
+func (retreatResolver) Resolve(in CombatInput, roller CombatRoller) CombatOutcome {
+	outcome := allOrNothingResolver{}.Resolve(in, roller)
+	if outcome.Won {
+		return outcome
+	}
+	for i := range in.Fighters {
+		if in.Fighters[i].CanRetreat && roller.Intn(100) < in.Config.Combat.RetreatChance {
+			outcome.Killed[i] = false
+			outcome.Retreated[i] = true
+		}
+	}
+	return outcome
+}
+
+// liveRoller rolls real dice for a fight, logging every roll and remembering
+// the rolls of each fighter
+type liveRoller struct {
+	game     *Game
+	dice     *RNG
+	fighters []Fighter
+	rolls    [][]int
+}
+
+func (r *liveRoller) Roll(fighter int) int {
+	result := r.game.rollDice(r.dice, r.fighters[fighter].ID)
+	r.rolls[fighter] = append(r.rolls[fighter], result)
+	return result
+}
+
+func (r *liveRoller) Intn(n int) int {
+	return r.dice.Intn(n)
+}
+
+// combatInput describes the fight of the players on the tile
+func (t *Tile) combatInput() CombatInput {
+	input := CombatInput{Zombies: t.Zombies, Config: t.game.config}
+	for _, playerPtr := range t.playerPtrs {
+		fighter := Fighter{ID: playerPtr.ID, Play: None, CanRetreat: playerPtr.Alive && playerPtr.cameFrom != nil}
+		if playerPtr.Play.isPlayable() && playerPtr.countCards(playerPtr.Play) > 0 {
+			fighter.Play = playerPtr.Play
+		}
+		input.Fighters = append(input.Fighters, fighter)
+	}
+	return input
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedRoller returns the scripted rolls and random numbers in order
+type scriptedRoller struct {
+	rolls   []int
+	numbers []int
+}
+
+func (r *scriptedRoller) Roll(fighter int) int {
+	roll := r.rolls[0]
+	r.rolls = r.rolls[1:]
+	return roll
+}
+
+func (r *scriptedRoller) Intn(n int) int {
+	number := r.numbers[0]
+	r.numbers = r.numbers[1:]
+	return number
+}
+
+func TestCombatResolvers(t *testing.T) {
+	fight := func(zombies int, plays ...Card) CombatInput {
+		input := CombatInput{Zombies: zombies, Config: NewDefaultConfig()}
+		for i, play := range plays {
+			input.Fighters = append(input.Fighters, Fighter{ID: string(rune('a' + i)), Play: play})
+		}
+		return input
+	}
+
+	t.Run("all or nothing kills everyone on a loss", func(t *testing.T) {
+		outcome := allOrNothingResolver{}.Resolve(fight(7, None, None), &scriptedRoller{rolls: []int{3, 4}})
+
+		assert.False(t, outcome.Won)
+		assert.Equal(t, 0, outcome.ZombiesKilled)
+		assert.Equal(t, []bool{true, true}, outcome.Killed)
+	})
+
+	t.Run("weapons and dice set the strength", func(t *testing.T) {
+		input := fight(4, Weapon, Dice)
+		input.Config.Combat.DiceEffect = DiceEffectExtraDie
+
+		outcome := allOrNothingResolver{}.Resolve(input, &scriptedRoller{rolls: []int{2, 5}})
+
+		assert.Equal(t, []int{3, 7}, outcome.Strengths)
+		assert.True(t, outcome.Won)
+		assert.Equal(t, 4, outcome.ZombiesKilled)
+	})
+
+	t.Run("attrition leftover zombies kill the weakest", func(t *testing.T) {
+		outcome := attritionResolver{}.Resolve(fight(8, None, None, None), &scriptedRoller{rolls: []int{4, 1, 2}})
+
+		assert.False(t, outcome.Won)
+		assert.Equal(t, 7, outcome.ZombiesKilled)
+		assert.Equal(t, []bool{false, true, false}, outcome.Killed)
+	})
+
+	t.Run("attrition wins when every zombie dies", func(t *testing.T) {
+		outcome := attritionResolver{}.Resolve(fight(5, None, None), &scriptedRoller{rolls: []int{3, 2}})
+
+		assert.True(t, outcome.Won)
+		assert.Equal(t, 5, outcome.ZombiesKilled)
+		assert.Equal(t, []bool{false, false}, outcome.Killed)
+	})
+
+	t.Run("duels split the zombies among the players", func(t *testing.T) {
+		// 5 zombies: the first player faces 3, the second 2
+		outcome := duelsResolver{}.Resolve(fight(5, None, None), &scriptedRoller{rolls: []int{3, 3}})
+
+		assert.False(t, outcome.Won)
+		assert.Equal(t, 2, outcome.ZombiesKilled)
+		assert.Equal(t, []bool{true, false}, outcome.Killed)
+	})
+
+	t.Run("retreat lets players who moved in escape a lost fight", func(t *testing.T) {
+		input := fight(9, None, None, None)
+		input.Config.Combat.RetreatChance = 50
+		input.Fighters[0].CanRetreat = true
+		input.Fighters[1].CanRetreat = true
+
+		outcome := retreatResolver{}.Resolve(input, &scriptedRoller{rolls: []int{1, 1, 1}, numbers: []int{49, 50}})
+
+		assert.False(t, outcome.Won)
+		assert.Equal(t, []bool{true, false, false}, outcome.Retreated)
+		assert.Equal(t, []bool{false, true, true}, outcome.Killed, "a player who stayed has nowhere to fall back to")
+	})
+
+	t.Run("retreating players fall back to the tile they came from", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Combat.Resolver = CombatResolverRetreat
+		ts.game.config.Combat.RetreatChance = 100
+		ts.setupTile(5, 4, Farm, 0)
+		ts.setupTile(5, 5, Farm, 50)
+		player := ts.getPlayer(ts.createPlayerAt(5, 4))
+		player.Direction = South
+
+		ts.playerMap.move()
+		ts.gameMap.handleCombat()
+
+		assert.True(t, player.Alive)
+		ts.assertPlayerPosition(player.ID, 5, 4, "the player should be back where it came from")
+		assert.Empty(t, ts.gameMap.getTileFromPos(5, 5).playerPtrs)
+		moves := ts.game.events.GetPlayerEvents(player.ID, EventFilters{EventType: EventPlayerMove})
+		require.NotEmpty(t, moves)
+		assert.Equal(t, "retreated", moves[len(moves)-1].Details["reason"])
+		events := ts.game.events.GetPlayerEvents("", EventFilters{EventType: EventCombatResult})
+		require.Len(t, events, 1)
+		details := events[0].Details
+		assert.Equal(t, false, details["combat_won"])
+		assert.Equal(t, 0, details["players_killed"])
+		assert.Equal(t, []string{player.ID}, details["players_retreated"])
+		assert.Equal(t, CombatResolverRetreat, details["resolver"])
+		assert.Equal(t, 50, details["zombies_after"])
+	})
+
+	t.Run("attrition on a tile removes the killed zombies", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Combat.Resolver = CombatResolverAttrition
+		ts.game.config.Combat.WeaponStrength = 3
+		ts.setupTile(5, 5, Farm, 5)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+		player.setHand([]Card{Weapon})
+		player.Play = Weapon
+
+		ts.gameMap.getTileFromPos(5, 5).resolveCombat(NewRNG(9))
+
+		assert.False(t, player.Alive)
+		assert.Empty(t, player.Cards, "the weapon is used up")
+		events := ts.game.events.GetPlayerEvents("", EventFilters{EventType: EventCombatResult})
+		require.Len(t, events, 1)
+		assert.Equal(t, 3, events[0].Details["zombies_killed"])
+		assert.Equal(t, 5, events[0].Details["zombies_before"])
+	})
+
+	t.Run("config rejects unknown resolvers and chances", func(t *testing.T) {
+		config := NewDefaultConfig()
+		config.Combat.Resolver = "coin_flip"
+		config.Combat.RetreatChance = -1
+
+		err := config.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Combat.Resolver")
+		assert.Contains(t, err.Error(), "Combat.RetreatChance")
+	})
+}
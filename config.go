@@ -1,40 +1,79 @@
 package main
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
 // Config holds all game configuration values
 type Config struct {
 	Map struct {
-		Width  int
-		Height int
+		Width          int
+		Height         int
+		Generator      string          // "uniform" or "clustered"
+		TerrainWeights map[Terrain]int // relative share of each terrain on generated maps
+		MinLabDistance int             // steps between any two laboratories, 0 allows neighbours
+		Seed           int64           // 0 draws one from the game seed, the game records the seed used
+		File           string          // map file to load instead of generating a map, see mapfile.go
 	}
 	Game struct {
 		BotNumber        int
+		BotStrategies    []string // strategy names assigned to the bots round robin, empty means baseline
 		TurnLength       int
+		MinTurnLength    int // seconds a turn lasts at least when all players are ready early, capped by TurnLength
 		MaxTurns         int
 		VictoryNumber    int
 		DefaultDirection Direction
+		Seed             int64    // 0 picks a seed from the clock
+		Phases           []string // names of the tick phases in the order they run, empty runs DefaultPhases; must include every built-in phase
 	}
 	Combat struct {
 		ZombieCutoff    int
 		WeaponStrength  int
 		PlayerMinAttack int
 		PlayerMaxAttack int
+		DiceEffect      string // what playing a Dice card does, "reroll" or "extra_die"
+		DiceDropChance  int    // percent chance for every player on a tile that won a combat to find a Dice card
+		Resolver        string // combat rule: "all_or_nothing", "attrition", "duels" or "retreat"
+		RetreatChance   int    // retreat resolver: percent chance for every player who moved onto the tile this tick to fall back alive to the tile it came from after a lost fight
 	}
 	Player struct {
 		NameMaxLength int
+		HandLimit     int             // cards a player keeps at the end of a tick, one more fits during the tick
+		VisionRadius  int             // tiles a player sees in every direction
+		TerrainVision map[Terrain]int // added to VisionRadius while standing on the terrain
 	}
 	Api struct {
 		DefaultReportedTurns int
 	}
 	Server struct {
-		IDSalt string
+		IDSalt     string // Key for player tokens, empty generates a random salt per game
+		AdminToken string // Bearer token for the admin endpoints, empty disables them
+		Verbose    bool   // print the map after every tick
+	}
+	EventLog struct {
+		Backend      string // "memory" or "file"
+		Directory    string // file backend: each game logs to its own subdirectory
+		MaxFileBytes int64  // file backend: size at which a new segment is started
+	}
+	Snapshot struct {
+		Directory string // a snapshot of each game is written here after every tick, empty disables
 	}
 	TerrainResources map[Terrain]TerrainReward
 }
 
 // TerrainReward defines what resources a terrain type provides
 type TerrainReward struct {
-	amount    int
-	givesCard Card
+	Amount     int
+	GivesCard  Card
+	DiceChance int `json:",omitempty"` // percent chance per tick to find a Dice card as well
 }
 
 // NewDefaultConfig creates a new configuration with default values
@@ -44,38 +83,410 @@ func NewDefaultConfig() *Config {
 	// Map configuration
 	config.Map.Width = 50
 	config.Map.Height = 50
+	config.Map.Generator = MapGeneratorUniform
+	config.Map.TerrainWeights = map[Terrain]int{Forest: 1, Farm: 1, City: 1, Laboratory: 1}
+	config.Map.MinLabDistance = 0
+	config.Map.Seed = 0
 
 	// Game configuration
 	config.Game.BotNumber = 0
 	config.Game.TurnLength = 15
+	config.Game.MinTurnLength = 3
 	config.Game.MaxTurns = 500
 	config.Game.VictoryNumber = 2
 	config.Game.DefaultDirection = South
+	config.Game.Seed = 0
+	config.Game.Phases = DefaultPhases()
 
 	// Combat configuration
 	config.Combat.ZombieCutoff = 3
 	config.Combat.WeaponStrength = 3
 	config.Combat.PlayerMinAttack = 1
 	config.Combat.PlayerMaxAttack = 6
+	config.Combat.DiceEffect = DiceEffectReroll
+	config.Combat.DiceDropChance = 20
+	config.Combat.Resolver = CombatResolverAllOrNothing
+	config.Combat.RetreatChance = 50
 
 	// Player configuration
 	config.Player.NameMaxLength = 20
+	config.Player.HandLimit = 4
+	config.Player.VisionRadius = 1
+	config.Player.TerrainVision = map[Terrain]int{Forest: -1, City: 1}
 
 	config.Api.DefaultReportedTurns = 5
 
 	// Server configuration
-	config.Server.IDSalt = "6LIBN8OWPzTKctUvbZtXV2mFn2tCq3qZKjHYbTTnLWtu6oGTU3ow3tuNx9SBTuND"
+	config.Server.IDSalt = ""
+
+	// Event log configuration
+	config.EventLog.Backend = EventLogBackendMemory
+	config.EventLog.Directory = "events"
+	config.EventLog.MaxFileBytes = 10 << 20
+
+	// Snapshots are opt-in
+	config.Snapshot.Directory = ""
 
 	// Terrain resources configuration
 	config.TerrainResources = map[Terrain]TerrainReward{
-		City:       {amount: 1, givesCard: Weapon},
-		Forest:     {amount: 2, givesCard: Wood},
-		Farm:       {amount: 1, givesCard: Food},
-		Laboratory: {amount: 1, givesCard: Research},
+		City:       {Amount: 1, GivesCard: Weapon},
+		Forest:     {Amount: 2, GivesCard: Wood},
+		Farm:       {Amount: 1, GivesCard: Food},
+		Laboratory: {Amount: 1, GivesCard: Research},
 	}
 
 	return config
 }
 
-// Global configuration instance
+// LoadConfig reads a JSON or YAML file and merges it over the default configuration.
+// Fields missing from the file keep their default values. Entries in TerrainResources
+// replace the default entry for that terrain as a whole.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	config := NewDefaultConfig()
+	if err := config.merge(data, filepath.Ext(path)); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+	return config, nil
+}
+
+// merge decodes data on top of the current values. YAML is converted to JSON first
+// so that both formats share the JSON (un)marshalers of Terrain, Card and Direction.
+func (c *Config) merge(data []byte, ext string) error {
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		data = converted
+	case ".json", "":
+	default:
+		return fmt.Errorf("unsupported config format %q (use .json, .yaml or .yml)", ext)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(c)
+}
+
+// clientConfigSections are the parts of the config a client may set when it
+// creates a game through POST /games. Server, event log and snapshot settings
+// and map files stay up to the operator.
+var clientConfigSections = []string{"Map", "Game", "Combat", "Player", "TerrainResources"}
+
+// Limits of the settings a client may choose when it creates a game, for every
+// setting whose cost to the server grows with its value
+const (
+	clientMaxConfigBytes  = 64 << 10
+	clientMaxMapSize      = 100
+	clientMaxTerrainShare = 100 // largest Map.TerrainWeights entry
+	clientMaxLabDistance  = 10
+	clientMaxBots         = 100
+	clientMinTurnLength   = 5
+	clientMaxTurnLength   = 3600
+	clientMaxTurns        = 1000
+	clientMaxPhases       = 20
+	clientMaxNameLength   = 50
+	clientMaxHandLimit    = 20
+	clientMaxVision       = 10 // Player.VisionRadius plus the largest Player.TerrainVision bonus
+	clientMaxAmount       = 10 // TerrainResources amount per tick
+)
+
+// mergeClient merges the JSON config of a client into c. Sections outside
+// clientConfigSections and Map.File are rejected, as are changed settings
+// outside the client limits. Settings the client leaves alone keep the
+// operator's values even if those exceed the limits.
+func (c *Config) mergeClient(data []byte) error {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+	for name, section := range sections {
+		allowed := false
+		for _, allowedName := range clientConfigSections {
+			// encoding/json matches field names case-insensitively
+			allowed = allowed || strings.EqualFold(name, allowedName)
+		}
+		if !allowed {
+			return ConfigError{name, "cannot be set when creating a game"}
+		}
+		if strings.EqualFold(name, "Map") {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(section, &fields); err != nil {
+				return err
+			}
+			for field := range fields {
+				if strings.EqualFold(field, "File") {
+					return ConfigError{"Map.File", "cannot be set when creating a game"}
+				}
+			}
+		}
+	}
+	base := c.clone()
+	if err := c.merge(data, ".json"); err != nil {
+		return err
+	}
+
+	var errs []error
+	limit := func(field string, value, baseValue, min, max int) {
+		if value != baseValue && (value < min || value > max) {
+			errs = append(errs, ConfigError{field, fmt.Sprintf("must be from %d to %d when creating a game, got %d", min, max, value)})
+		}
+	}
+	limit("Map.Width", c.Map.Width, base.Map.Width, 1, clientMaxMapSize)
+	limit("Map.Height", c.Map.Height, base.Map.Height, 1, clientMaxMapSize)
+	limit("Map.MinLabDistance", c.Map.MinLabDistance, base.Map.MinLabDistance, 0, clientMaxLabDistance)
+	weightsChanged, totalWeight := len(c.Map.TerrainWeights) != len(base.Map.TerrainWeights), 0
+	for terrain, weight := range c.Map.TerrainWeights {
+		limit("Map.TerrainWeights."+terrain.toString(), weight, base.Map.TerrainWeights[terrain], 0, clientMaxTerrainShare)
+		weightsChanged = weightsChanged || weight != base.Map.TerrainWeights[terrain]
+		totalWeight += weight
+	}
+	// Laboratories are spaced pairwise, so lab-heavy maps are the costly ones
+	if labWeight := c.Map.TerrainWeights[Laboratory]; weightsChanged && labWeight*4 > totalWeight {
+		errs = append(errs, ConfigError{"Map.TerrainWeights.Laboratory",
+			fmt.Sprintf("must be at most a quarter of the total weight when creating a game, got %d of %d", labWeight, totalWeight)})
+	}
+	limit("Game.BotNumber", c.Game.BotNumber, base.Game.BotNumber, 0, clientMaxBots)
+	limit("Game.TurnLength", c.Game.TurnLength, base.Game.TurnLength, clientMinTurnLength, clientMaxTurnLength)
+	limit("Game.MinTurnLength", c.Game.MinTurnLength, base.Game.MinTurnLength, 1, clientMaxTurnLength)
+	limit("Game.MaxTurns", c.Game.MaxTurns, base.Game.MaxTurns, 1, clientMaxTurns)
+	limit("Game.Phases", len(c.Game.Phases), len(base.Game.Phases), 0, clientMaxPhases)
+	limit("Player.NameMaxLength", c.Player.NameMaxLength, base.Player.NameMaxLength, 1, clientMaxNameLength)
+	limit("Player.HandLimit", c.Player.HandLimit, base.Player.HandLimit, 1, clientMaxHandLimit)
+	limit("Player.VisionRadius", c.Player.VisionRadius+c.largestVisionBonus(),
+		base.Player.VisionRadius+base.largestVisionBonus(), 0, clientMaxVision)
+	for terrain, reward := range c.TerrainResources {
+		limit("TerrainResources."+terrain.toString()+".Amount", reward.Amount, base.TerrainResources[terrain].Amount, 0, clientMaxAmount)
+	}
+	return errors.Join(errs...)
+}
+
+// largestVisionBonus is the largest Player.TerrainVision modifier, at least 0
+func (c *Config) largestVisionBonus() int {
+	largest := 0
+	for _, bonus := range c.Player.TerrainVision {
+		largest = max(largest, bonus)
+	}
+	return largest
+}
+
+// ConfigError describes a single invalid configuration field
+type ConfigError struct {
+	Field   string
+	Message string
+}
+
+func (e ConfigError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Validate checks the configuration for values the game cannot run with.
+// All problems are reported together, one ConfigError per field.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, ConfigError{field, fmt.Sprintf(format, args...)})
+	}
+
+	if c.Map.Width <= 0 {
+		fail("Map.Width", "must be positive, got %d", c.Map.Width)
+	}
+	if c.Map.Height <= 0 {
+		fail("Map.Height", "must be positive, got %d", c.Map.Height)
+	}
+
+	if _, exists := mapGenerators[c.Map.Generator]; !exists {
+		fail("Map.Generator", "unknown generator %q (use one of %s)",
+			c.Map.Generator, strings.Join(MapGeneratorNames(), ", "))
+	}
+	totalWeight := 0
+	for terrain, weight := range c.Map.TerrainWeights {
+		if terrain < Forest || terrain > Edge {
+			fail(fmt.Sprintf("Map.TerrainWeights.%d", terrain), "is not a terrain")
+			continue
+		}
+		field := "Map.TerrainWeights." + terrain.toString()
+		if terrain == Edge {
+			fail(field, "is not a terrain of the map")
+		} else if weight < 0 {
+			fail(field, "must not be negative, got %d", weight)
+		} else {
+			totalWeight += weight
+		}
+	}
+	if totalWeight <= 0 {
+		fail("Map.TerrainWeights", "must give at least one terrain a positive weight")
+	}
+	if c.Map.MinLabDistance < 0 {
+		fail("Map.MinLabDistance", "must not be negative, got %d", c.Map.MinLabDistance)
+	}
+
+	if c.Game.BotNumber < 0 {
+		fail("Game.BotNumber", "must not be negative, got %d", c.Game.BotNumber)
+	}
+	for i, name := range c.Game.BotStrategies {
+		if _, exists := botStrategies[name]; !exists {
+			fail(fmt.Sprintf("Game.BotStrategies[%d]", i), "unknown strategy %q (use one of %s)",
+				name, strings.Join(BotStrategyNames(), ", "))
+		}
+	}
+	for i, name := range c.Game.Phases {
+		if _, exists := lookupPhase(name); !exists {
+			fail(fmt.Sprintf("Game.Phases[%d]", i), "unknown phase %q (use one of %s)",
+				name, strings.Join(PhaseNames(), ", "))
+		}
+	}
+	// Dropping a built-in phase such as bots or trade silently disables a feature,
+	// so an explicit order has to name all of them
+	if len(c.Game.Phases) > 0 {
+		configured := make(map[string]bool, len(c.Game.Phases))
+		for _, name := range c.Game.Phases {
+			configured[name] = true
+		}
+		for _, builtin := range DefaultPhases() {
+			if !configured[builtin] {
+				fail("Game.Phases", "is missing the built-in phase %q", builtin)
+			}
+		}
+	}
+	if c.Game.TurnLength <= 0 {
+		fail("Game.TurnLength", "must be positive, got %d", c.Game.TurnLength)
+	}
+	if c.Game.MinTurnLength < 0 {
+		fail("Game.MinTurnLength", "must not be negative, got %d", c.Game.MinTurnLength)
+	}
+	if c.Game.MaxTurns <= 0 {
+		fail("Game.MaxTurns", "must be positive, got %d", c.Game.MaxTurns)
+	}
+	if c.Game.VictoryNumber <= 0 {
+		fail("Game.VictoryNumber", "must be positive, got %d", c.Game.VictoryNumber)
+	}
+	if !c.Game.DefaultDirection.isValid() {
+		fail("Game.DefaultDirection", "unknown direction %d", c.Game.DefaultDirection)
+	}
+
+	if c.Combat.ZombieCutoff < 0 {
+		fail("Combat.ZombieCutoff", "must not be negative, got %d", c.Combat.ZombieCutoff)
+	}
+	if c.Combat.WeaponStrength < 0 {
+		fail("Combat.WeaponStrength", "must not be negative, got %d", c.Combat.WeaponStrength)
+	}
+	if c.Combat.PlayerMinAttack < 0 {
+		fail("Combat.PlayerMinAttack", "must not be negative, got %d", c.Combat.PlayerMinAttack)
+	}
+	if c.Combat.PlayerMinAttack > c.Combat.PlayerMaxAttack {
+		fail("Combat.PlayerMinAttack", "must not exceed Combat.PlayerMaxAttack (%d > %d)",
+			c.Combat.PlayerMinAttack, c.Combat.PlayerMaxAttack)
+	}
+	if _, exists := diceEffects[c.Combat.DiceEffect]; !exists {
+		fail("Combat.DiceEffect", "unknown effect %q (use one of %s)",
+			c.Combat.DiceEffect, strings.Join(DiceEffectNames(), ", "))
+	}
+	if c.Combat.DiceDropChance < 0 || c.Combat.DiceDropChance > 100 {
+		fail("Combat.DiceDropChance", "must be a percentage from 0 to 100, got %d", c.Combat.DiceDropChance)
+	}
+	if _, exists := combatResolvers[c.Combat.Resolver]; !exists {
+		fail("Combat.Resolver", "unknown resolver %q (use one of %s)",
+			c.Combat.Resolver, strings.Join(CombatResolverNames(), ", "))
+	}
+	if c.Combat.RetreatChance < 0 || c.Combat.RetreatChance > 100 {
+		fail("Combat.RetreatChance", "must be a percentage from 0 to 100, got %d", c.Combat.RetreatChance)
+	}
+
+	if c.Player.NameMaxLength <= 0 {
+		fail("Player.NameMaxLength", "must be positive, got %d", c.Player.NameMaxLength)
+	}
+	if c.Player.HandLimit <= 0 {
+		fail("Player.HandLimit", "must be positive, got %d", c.Player.HandLimit)
+	}
+	if c.Player.VisionRadius < 0 {
+		fail("Player.VisionRadius", "must not be negative, got %d", c.Player.VisionRadius)
+	}
+	for terrain := range c.Player.TerrainVision {
+		if terrain < Forest || terrain >= Edge {
+			fail(fmt.Sprintf("Player.TerrainVision.%d", terrain), "is not a terrain of the map")
+		}
+	}
+	// A larger radius only adds Edge tiles to every view at a growing cost
+	if mapSize, vision := max(c.Map.Width, c.Map.Height), c.Player.VisionRadius+c.largestVisionBonus(); vision > mapSize {
+		fail("Player.VisionRadius", "plus the largest Player.TerrainVision bonus must not exceed the map size %d, got %d",
+			mapSize, vision)
+	}
+	if c.Api.DefaultReportedTurns <= 0 {
+		fail("Api.DefaultReportedTurns", "must be positive, got %d", c.Api.DefaultReportedTurns)
+	}
+
+	switch c.EventLog.Backend {
+	case EventLogBackendMemory:
+	case EventLogBackendFile:
+		if c.EventLog.Directory == "" {
+			fail("EventLog.Directory", "must not be empty for the file backend")
+		}
+		if c.EventLog.MaxFileBytes <= 0 {
+			fail("EventLog.MaxFileBytes", "must be positive, got %d", c.EventLog.MaxFileBytes)
+		}
+	default:
+		fail("EventLog.Backend", "unknown backend %q (use %q or %q)",
+			c.EventLog.Backend, EventLogBackendMemory, EventLogBackendFile)
+	}
+
+	for _, terrain := range terrainTypes {
+		if terrain == Edge {
+			continue
+		}
+		field := "TerrainResources." + terrain.toString()
+		reward, exists := c.TerrainResources[terrain]
+		if !exists {
+			fail(field, "missing reward")
+			continue
+		}
+		if reward.Amount < 0 {
+			fail(field+".Amount", "must not be negative, got %d", reward.Amount)
+		}
+		if !reward.GivesCard.isValid() {
+			fail(field+".GivesCard", "unknown card %d", reward.GivesCard)
+		}
+		if reward.DiceChance < 0 || reward.DiceChance > 100 {
+			fail(field+".DiceChance", "must be a percentage from 0 to 100, got %d", reward.DiceChance)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// clone returns a deep copy, so games created from the same base config
+// can change their rules independently.
+func (c *Config) clone() *Config {
+	copied := *c
+	copied.Map.TerrainWeights = make(map[Terrain]int, len(c.Map.TerrainWeights))
+	for terrain, weight := range c.Map.TerrainWeights {
+		copied.Map.TerrainWeights[terrain] = weight
+	}
+	copied.Player.TerrainVision = make(map[Terrain]int, len(c.Player.TerrainVision))
+	for terrain, modifier := range c.Player.TerrainVision {
+		copied.Player.TerrainVision[terrain] = modifier
+	}
+	copied.Game.BotStrategies = append([]string(nil), c.Game.BotStrategies...)
+	copied.Game.Phases = append([]string(nil), c.Game.Phases...)
+	copied.TerrainResources = make(map[Terrain]TerrainReward, len(c.TerrainResources))
+	for terrain, reward := range c.TerrainResources {
+		copied.TerrainResources[terrain] = reward
+	}
+	return &copied
+}
+
+// Base configuration every new game starts from
 var gameConfig = NewDefaultConfig()
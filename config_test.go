@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("json file is merged over defaults", func(t *testing.T) {
+		path := writeConfigFile(t, "rules.json", `{
+			"Map": {"Width": 10},
+			"Game": {"DefaultDirection": "stay"},
+			"TerrainResources": {"Forest": {"Amount": 3, "GivesCard": "Wood"}}
+		}`)
+
+		config, err := LoadConfig(path)
+
+		require.NoError(t, err)
+		defaults := NewDefaultConfig()
+		assert.Equal(t, 10, config.Map.Width, "Width should be overridden")
+		assert.Equal(t, defaults.Map.Height, config.Map.Height, "Height should keep its default")
+		assert.Equal(t, Stay, config.Game.DefaultDirection, "Direction should be parsed by name")
+		assert.Equal(t, TerrainReward{Amount: 3, GivesCard: Wood}, config.TerrainResources[Forest])
+		assert.Equal(t, defaults.TerrainResources[City], config.TerrainResources[City], "Other terrains keep defaults")
+	})
+
+	t.Run("yaml file is merged over defaults", func(t *testing.T) {
+		path := writeConfigFile(t, "rules.yaml", `
+Combat:
+  ZombieCutoff: 5
+TerrainResources:
+  laboratory:
+    Amount: 2
+    GivesCard: research
+`)
+
+		config, err := LoadConfig(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, config.Combat.ZombieCutoff)
+		assert.Equal(t, NewDefaultConfig().Combat.WeaponStrength, config.Combat.WeaponStrength)
+		assert.Equal(t, TerrainReward{Amount: 2, GivesCard: Research}, config.TerrainResources[Laboratory])
+	})
+
+	t.Run("unknown fields are rejected", func(t *testing.T) {
+		path := writeConfigFile(t, "rules.json", `{"Map": {"Widht": 10}}`)
+
+		_, err := LoadConfig(path)
+
+		assert.ErrorContains(t, err, "Widht")
+	})
+
+	t.Run("unknown direction is rejected", func(t *testing.T) {
+		path := writeConfigFile(t, "rules.json", `{"Game": {"DefaultDirection": "up"}}`)
+
+		_, err := LoadConfig(path)
+
+		assert.ErrorContains(t, err, "unknown direction")
+	})
+
+	t.Run("unsupported extension is rejected", func(t *testing.T) {
+		path := writeConfigFile(t, "rules.ini", `Width=10`)
+
+		_, err := LoadConfig(path)
+
+		assert.ErrorContains(t, err, "unsupported config format")
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Run("default config is valid", func(t *testing.T) {
+		assert.NoError(t, NewDefaultConfig().Validate())
+	})
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		config := NewDefaultConfig()
+		config.Map.Width = 0
+		config.Combat.PlayerMinAttack = 7
+		config.Game.DefaultDirection = Direction(42)
+		delete(config.TerrainResources, Farm)
+
+		err := config.Validate()
+
+		var fields []string
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var configErr ConfigError
+			require.True(t, errors.As(e, &configErr))
+			fields = append(fields, configErr.Field)
+		}
+		assert.ElementsMatch(t, []string{
+			"Map.Width",
+			"Combat.PlayerMinAttack",
+			"Game.DefaultDirection",
+			"TerrainResources.Farm",
+		}, fields)
+	})
+
+	t.Run("edge terrain does not need a reward", func(t *testing.T) {
+		config := NewDefaultConfig()
+		delete(config.TerrainResources, Edge)
+
+		assert.NoError(t, config.Validate())
+	})
+}
@@ -6,7 +6,15 @@ type IntTuple struct {
 }
 
 type ConfigResponse struct {
-	TurnLength int
-	TurnTime   int
-	HaveWon    bool
-}
\ No newline at end of file
+	TurnLength     int
+	TurnTime       int
+	RemainingTurns int
+	HaveWon        bool
+	Seed           int64
+	MapSeed        int64
+}
+
+type NewPlayerResponse struct {
+	ID    string
+	Token string
+}
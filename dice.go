@@ -0,0 +1,56 @@
+package main
+
+import "sort"
+
+// Names of the effects a Dice card can have in combat
+const (
+	DiceEffectReroll   = "reroll"    // roll a second time and keep the better roll
+	DiceEffectExtraDie = "extra_die" // roll a second die and add both rolls
+)
+
+// diceEffects combine a player's regular roll with the roll a Dice card grants
+var diceEffects = map[string]func(roll, bonusRoll int) int{
+	DiceEffectReroll:   func(roll, bonusRoll int) int { return max(roll, bonusRoll) },
+	DiceEffectExtraDie: func(roll, bonusRoll int) int { return roll + bonusRoll },
+}
+
+// DiceEffectNames returns the names of all dice effects in sorted order
+func DiceEffectNames() []string {
+	names := make([]string, 0, len(diceEffects))
+	for name := range diceEffects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// useDice removes the Dice card the player fought with from its hand and logs
+// the rolls it made
+func (t *Tile) useDice(player *Player, rolls []int, strength int) {
+	slot, _ := hasCardWhere(player.Cards, Dice)
+	player.removeCard(slot)
+
+	t.game.events.LogEvent(EventCardUsed, player.ID, map[string]interface{}{
+		"card":      Dice.String(),
+		"card_slot": slot,
+		"x":         t.XPos,
+		"y":         t.YPos,
+		"effect":    t.game.config.Combat.DiceEffect,
+		"rolls":     rolls,
+		"strength":  strength,
+	})
+}
+
+// dropDice gives every living player on the tile a Dice card with a chance of
+// Combat.DiceDropChance percent, after the players have won a combat
+func (t *Tile) dropDice(dice *RNG) {
+	chance := t.game.config.Combat.DiceDropChance
+	if chance <= 0 {
+		return
+	}
+	for _, playerPtr := range t.playerPtrs {
+		if playerPtr.Alive && dice.Intn(100) < chance {
+			playerPtr.drawCard(Dice, "combat")
+		}
+	}
+}
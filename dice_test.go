@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiceCard(t *testing.T) {
+	// combatResult runs the combat on the tile and returns its combat_result details
+	combatResult := func(t *testing.T, ts *TestSuite, x, y int, dice *RNG) map[string]interface{} {
+		ts.gameMap.getTileFromPos(x, y).resolveCombat(dice)
+		events := ts.game.events.GetPlayerEvents("", EventFilters{EventType: EventCombatResult})
+		require.NotEmpty(t, events)
+		return events[0].Details
+	}
+
+	t.Run("playing dice sets the play order", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+		player.setHand([]Card{Dice, Food})
+
+		player.cardInput("Dice")
+
+		assert.Equal(t, Dice, player.Play)
+		assert.Equal(t, None, player.Consume)
+	})
+
+	for _, tc := range []struct {
+		effect string
+		apply  func(roll, bonusRoll int) int
+	}{
+		{DiceEffectReroll, func(roll, bonusRoll int) int { return max(roll, bonusRoll) }},
+		{DiceEffectExtraDie, func(roll, bonusRoll int) int { return roll + bonusRoll }},
+	} {
+		t.Run("effect "+tc.effect, func(t *testing.T) {
+			ts := setupTestSuite(t)
+			ts.game.config.Combat.DiceEffect = tc.effect
+			ts.game.config.Combat.DiceDropChance = 0
+			ts.setupTile(5, 5, Farm, 0)
+			player := ts.getPlayer(ts.createPlayerAt(5, 5))
+			player.setHand([]Card{Food, Dice})
+			player.Play = Dice
+
+			expected := NewRNG(9)
+			config := ts.game.config.Combat
+			roll := expected.Intn(config.PlayerMaxAttack-config.PlayerMinAttack+1) + config.PlayerMinAttack
+			bonusRoll := expected.Intn(config.PlayerMaxAttack-config.PlayerMinAttack+1) + config.PlayerMinAttack
+
+			details := combatResult(t, ts, 5, 5, NewRNG(9))
+
+			assert.Equal(t, tc.apply(roll, bonusRoll), details["player_strength"])
+			assert.Equal(t, []string{player.ID}, details["dice_played"])
+			assert.Equal(t, tc.effect, details["dice_effect"])
+			assert.Equal(t, []Card{Food}, player.Cards, "the dice card is used up")
+
+			used := ts.game.events.GetPlayerEvents(player.ID, EventFilters{EventType: EventCardUsed})
+			require.Len(t, used, 1)
+			assert.Equal(t, []int{roll, bonusRoll}, used[0].Details["rolls"])
+		})
+	}
+
+	t.Run("dice play without a dice card rolls normally", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.setupTile(5, 5, Farm, 0)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+		player.Play = Dice
+
+		details := combatResult(t, ts, 5, 5, NewRNG(9))
+
+		assert.Empty(t, details["dice_played"])
+	})
+
+	t.Run("won combat can drop dice", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Combat.DiceDropChance = 100
+		ts.setupTile(5, 5, Farm, 0)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+
+		details := combatResult(t, ts, 5, 5, NewRNG(9))
+
+		require.Equal(t, true, details["combat_won"])
+		assert.Equal(t, 1, player.countCards(Dice))
+		drawn := ts.game.events.GetPlayerEvents(player.ID, EventFilters{EventType: EventCardDrawn})
+		require.Len(t, drawn, 1)
+		assert.Equal(t, "combat", drawn[0].Details["source"])
+	})
+
+	t.Run("terrain can hand out dice", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		reward := ts.game.config.TerrainResources[Farm]
+		reward.DiceChance = 100
+		ts.game.config.TerrainResources[Farm] = reward
+		ts.setupTile(5, 5, Farm, 0)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+
+		ts.gameMap.getTileFromPos(5, 5).giveResources()
+
+		assert.Equal(t, 1, player.countCards(Dice))
+		assert.Equal(t, 2, player.countCards(Food))
+	})
+
+	t.Run("config rejects unknown effects and chances", func(t *testing.T) {
+		config := NewDefaultConfig()
+		config.Combat.DiceEffect = "loaded"
+		config.Combat.DiceDropChance = 101
+
+		err := config.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Combat.DiceEffect")
+		assert.Contains(t, err.Error(), "Combat.DiceDropChance")
+	})
+}
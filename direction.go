@@ -1,6 +1,10 @@
 package main
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 type Direction int
 
@@ -18,10 +22,27 @@ func (d Direction) toString() string {
 	return []string{"North", "East", "South", "West", "Stay"}[d]
 }
 
+func (d Direction) isValid() bool {
+	return d >= North && d <= Stay
+}
+
 func (d Direction) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d.toString())
 }
 
+func (d *Direction) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	dir, exists := directions[strings.ToLower(name)]
+	if !exists {
+		return fmt.Errorf("unknown direction %q", name)
+	}
+	*d = dir
+	return nil
+}
+
 var directions = map[string]Direction{
 	"north": North,
 	"east":  East,
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscard(t *testing.T) {
+	discardEvents := func(ts *TestSuite, playerID string) []GameEvent {
+		return ts.game.events.GetPlayerEvents(playerID, EventFilters{EventType: EventCardDiscarded})
+	}
+
+	t.Run("chosen card is discarded first", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		player := ts.getPlayer(ts.createPlayerAt(1, 1))
+		player.setHand([]Card{Research, Food, Wood, Weapon, Wood})
+		player.ResearchAcquisitionPos[0] = [2]int{3, 3}
+		player.selectDiscard(Food)
+
+		ts.playerMap.limitCards()
+
+		assert.Equal(t, []Card{Research, Wood, Weapon, Wood}, player.Cards)
+		assert.Equal(t, [2]int{3, 3}, player.ResearchAcquisitionPos[0])
+		assert.Len(t, player.ResearchAcquisitionPos, 4)
+		assert.Equal(t, None, player.Discard)
+		events := discardEvents(ts, player.ID)
+		require.Len(t, events, 1)
+		assert.Equal(t, "chosen", events[0].Details["reason"])
+		assert.Equal(t, Food.String(), events[0].Details["card"])
+	})
+
+	t.Run("hand within the limit keeps its cards", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		player := ts.getPlayer(ts.createPlayerAt(1, 1))
+		player.selectDiscard(Food)
+
+		ts.playerMap.limitCards()
+
+		assert.Equal(t, []Card{Food, Wood, Wood}, player.Cards)
+		assert.Empty(t, discardEvents(ts, player.ID))
+	})
+
+	t.Run("newest cards are forced out down to the hand limit", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Player.HandLimit = 2
+		player := ts.getPlayer(ts.createPlayerAt(1, 1))
+		player.setHand([]Card{Food, Wood, Weapon})
+		player.selectDiscard(Research) // not in the hand
+
+		ts.playerMap.limitCards()
+
+		assert.Equal(t, []Card{Food, Wood}, player.Cards)
+		events := discardEvents(ts, player.ID)
+		require.Len(t, events, 1)
+		assert.Equal(t, "forced", events[0].Details["reason"])
+		assert.Equal(t, Weapon.String(), events[0].Details["card"])
+	})
+
+	t.Run("hand limit caps the cards gained during a tick", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Player.HandLimit = 3
+		ts.setupTile(1, 1, Forest, 0)
+		player := ts.getPlayer(ts.createPlayerAt(1, 1))
+
+		ts.gameMap.getTileFromPos(1, 1).giveResources()
+
+		assert.Equal(t, []Card{Food, Wood, Wood, Wood}, player.Cards, "one card over the limit fits until the end of the tick")
+	})
+
+	t.Run("discard endpoint selects the card", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		player := as.addPlayer("", "alice")
+
+		rec := as.requestWithToken(http.MethodPut, "/player/"+player.ID+"/discard/wood", "", player.Token)
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		game := as.registry.get(defaultGameID)
+		assert.Equal(t, Wood, game.pMap.Players[player.ID].Discard)
+		assert.Equal(t, http.StatusBadRequest,
+			as.requestWithToken(http.MethodPut, "/player/"+player.ID+"/discard/gold", "", player.Token).Code)
+		assert.Equal(t, http.StatusUnauthorized,
+			as.request(http.MethodPut, "/player/"+player.ID+"/discard/wood", "").Code)
+	})
+
+	t.Run("discard events can be queried by type", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		player := as.addPlayer("", "alice")
+
+		rec := as.requestWithToken(http.MethodGet, "/player/"+player.ID+"/events/type/card_discarded", "", player.Token)
+
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	})
+
+	t.Run("snapshots with empty slots restore compact hands", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		player := ts.getPlayer(ts.createPlayerAt(1, 1))
+		snapshot := ts.game.snapshot()
+		snapshot.Version = 1
+		snapshot.Players[0].Cards = []Card{Research, None, Food, None, None}
+		snapshot.Players[0].ResearchAcquisitionPos = [][2]int{{2, 2}, {-1, -1}, {-1, -1}, {-1, -1}, {-1, -1}}
+
+		restored, err := RestoreGame(roundTripSnapshot(t, snapshot))
+
+		require.NoError(t, err)
+		restoredPlayer := restored.pMap.Players[player.ID]
+		assert.Equal(t, []Card{Research, Food}, restoredPlayer.Cards)
+		assert.Equal(t, [][2]int{{2, 2}, {-1, -1}}, restoredPlayer.ResearchAcquisitionPos)
+	})
+
+	t.Run("current snapshots must not have empty slots", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.getPlayer(ts.createPlayerAt(1, 1))
+		snapshot := ts.game.snapshot()
+		snapshot.Players[0].Cards = []Card{Research, None}
+		snapshot.Players[0].ResearchAcquisitionPos = [][2]int{{2, 2}, {-1, -1}}
+
+		_, err := RestoreGame(snapshot)
+
+		assert.ErrorContains(t, err, "empty card")
+	})
+}
@@ -1,19 +1,19 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEventLogAccessControl(t *testing.T) {
 	// Setup test environment
 	ts := setupTestSuite(t)
-	defer func() {
-		// Reset global event logger after test
-		eventLogger = NewEventLogger()
-	}()
 
 	// Create test players
 	player1ID := ts.createPlayerAt(1, 1)
@@ -67,7 +67,7 @@ func TestEventLogAccessControl(t *testing.T) {
 	for _, event := range testEvents {
 		el.events = append(el.events, event)
 	}
-	eventLogger = el
+	ts.game.events = el
 
 	tests := []struct {
 		name            string
@@ -114,9 +114,9 @@ func TestEventLogAccessControl(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var events []GameEvent
 			if tt.filterType != "" {
-				events = eventLogger.GetPlayerEvents(tt.requestingPlayer, EventFilters{EventType: tt.filterType})
+				events = ts.game.events.GetPlayerEvents(tt.requestingPlayer, EventFilters{EventType: tt.filterType})
 			} else {
-				events = eventLogger.GetPlayerEvents(tt.requestingPlayer, EventFilters{})
+				events = ts.game.events.GetPlayerEvents(tt.requestingPlayer, EventFilters{})
 			}
 
 			// Verify expected number of events
@@ -166,3 +166,182 @@ func TestEventLogger_ConcurrentAccess(t *testing.T) {
 		}
 	})
 }
+
+func TestFileEventLogger(t *testing.T) {
+	t.Run("events are appended as json lines", func(t *testing.T) {
+		dir := t.TempDir()
+		l, err := NewFileEventLogger(dir, 1<<20)
+		require.NoError(t, err)
+
+		l.LogEvent(EventPlayerMove, "p1", map[string]interface{}{"to_x": 1})
+		l.LogEvent(EventPlayerDeath, "p2", map[string]interface{}{"reason": "combat"})
+		require.NoError(t, l.Close())
+
+		data, err := os.ReadFile(filepath.Join(dir, "events-000000.jsonl"))
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		assert.Len(t, lines, 2, "Each event should be one line")
+		assert.Contains(t, lines[0], `"type":"player_move"`)
+	})
+
+	t.Run("segments rotate when full", func(t *testing.T) {
+		dir := t.TempDir()
+		l, err := NewFileEventLogger(dir, 200)
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			l.LogEvent(EventPlayerMove, "p1", map[string]interface{}{"step": i})
+		}
+		require.NoError(t, l.Close())
+
+		segments, err := filepath.Glob(filepath.Join(dir, "events-*.jsonl"))
+		require.NoError(t, err)
+		assert.Greater(t, len(segments), 1, "Log should have rotated into several segments")
+	})
+
+	t.Run("log is replayed when reopened", func(t *testing.T) {
+		dir := t.TempDir()
+		l, err := NewFileEventLogger(dir, 200)
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			l.LogEvent(EventPlayerMove, "p1", map[string]interface{}{"step": i})
+		}
+		l.LogEvent(EventPlayerDeath, "p2", map[string]interface{}{"reason": "starvation"})
+		require.NoError(t, l.Close())
+
+		reopened, err := NewFileEventLogger(dir, 200)
+		require.NoError(t, err)
+		defer reopened.Close()
+		reopened.LogEvent(EventPlayerMove, "p1", map[string]interface{}{"step": 10})
+
+		events := reopened.GetPlayerEvents("p1", EventFilters{EventType: EventPlayerMove})
+		assert.Len(t, events, 11, "Replayed and new events should both be visible")
+		assert.Equal(t, float64(0), events[0].Details["step"], "Replay should keep the original order")
+		assert.Equal(t, int64(12), reopened.GetEventCount())
+		assert.Equal(t, int64(1), reopened.GetEventTypeCount(EventPlayerDeath))
+	})
+
+	t.Run("file backend is selected through config", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.EventLog.Backend = EventLogBackendFile
+		config.EventLog.Directory = t.TempDir()
+
+		game, err := NewGame("persistent", config)
+		require.NoError(t, err)
+
+		fileLogger, ok := game.events.(*FileEventLogger)
+		require.True(t, ok, "Game should use the file event logger")
+		defer fileLogger.Close()
+		assert.DirExists(t, filepath.Join(config.EventLog.Directory, "persistent"))
+	})
+
+	t.Run("new game does not inherit the log of an earlier game", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.EventLog.Backend = EventLogBackendFile
+		config.EventLog.Directory = t.TempDir()
+		config.Snapshot.Directory = ""
+
+		first, err := NewGame("default", config.clone())
+		require.NoError(t, err)
+		first.endTurn()
+		first.endTurn()
+		snapshot := first.snapshot()
+		require.NoError(t, first.events.(*FileEventLogger).Close())
+
+		fresh, err := NewGame("default", config.clone())
+		require.NoError(t, err)
+		assert.Zero(t, fresh.events.GetEventCount(), "a fresh game starts with an empty log")
+		archived, err := filepath.Glob(filepath.Join(config.EventLog.Directory, "default.archived-*"))
+		require.NoError(t, err)
+		assert.Len(t, archived, 1, "the earlier log is kept aside")
+
+		require.NoError(t, fresh.events.(*FileEventLogger).Close())
+		require.NoError(t, os.RemoveAll(filepath.Join(config.EventLog.Directory, "default")))
+		require.NoError(t, os.Rename(archived[0], filepath.Join(config.EventLog.Directory, "default")))
+		restored, err := RestoreGame(snapshot)
+		require.NoError(t, err)
+		defer restored.events.(*FileEventLogger).Close()
+		assert.Len(t, restored.events.GetPlayerEvents("", EventFilters{EventType: EventGameTick}), 2,
+			"a restored game replays its log")
+	})
+}
+
+func TestEventTurns(t *testing.T) {
+	t.Run("last turns filter counts the current turn", func(t *testing.T) {
+		logger := NewEventLogger()
+		for turn := int64(0); turn <= 3; turn++ {
+			logger.BeginTurn(turn)
+			logger.LogEvent(EventPlayerMove, "p1", map[string]interface{}{"turn": turn})
+			logger.LogEvent(EventPlayerMove, "p1", map[string]interface{}{"turn": turn})
+		}
+
+		turnsOf := func(events []GameEvent) []int64 {
+			var turns []int64
+			for _, event := range events {
+				turns = append(turns, event.Turn)
+			}
+			return turns
+		}
+		assert.Equal(t, []int64{3, 3}, turnsOf(logger.GetPlayerEvents("p1", EventFilters{LastTurns: 1})))
+		assert.Equal(t, []int64{2, 2, 3, 3}, turnsOf(logger.GetPlayerEvents("p1", EventFilters{LastTurns: 2})))
+		assert.Len(t, logger.GetPlayerEvents("p1", EventFilters{LastTurns: 4}), 8)
+		assert.Len(t, logger.GetPlayerEvents("p1", EventFilters{LastTurns: 10}), 8)
+		assert.Len(t, logger.GetPlayerEvents("p1", EventFilters{}), 8)
+	})
+
+	t.Run("each tick begins a turn and logs a summary", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		playerID := ts.createPlayerAt(1, 1)
+
+		ts.game.endTurn()
+		ts.game.endTurn()
+
+		ticks := ts.game.events.GetPlayerEvents(playerID, EventFilters{EventType: EventGameTick})
+		require.Len(t, ticks, 2)
+		assert.Equal(t, int64(1), ticks[0].Turn)
+		assert.Equal(t, int64(2), ticks[1].Turn)
+		assert.Equal(t, int64(2), ticks[1].Details["turn"])
+		assert.Equal(t, ts.game.state.getRemainingTurns(), ticks[1].Details["remaining_turns"])
+		assert.Contains(t, ticks[1].Details, "zombies")
+		assert.Contains(t, ticks[1].Details, "players_alive")
+
+		joins := ts.game.events.GetPlayerEvents(playerID, EventFilters{EventType: EventPlayerJoin})
+		require.Len(t, joins, 1)
+		assert.Equal(t, int64(0), joins[0].Turn, "Events before the first tick belong to turn 0")
+
+		lastTurn := ts.game.events.GetPlayerEvents(playerID, EventFilters{LastTurns: 1})
+		require.NotEmpty(t, lastTurn)
+		for _, event := range lastTurn {
+			assert.Equal(t, int64(2), event.Turn)
+		}
+	})
+
+	t.Run("turn survives a restart of the file log", func(t *testing.T) {
+		dir := t.TempDir()
+		logger, err := NewFileEventLogger(dir, 1<<20)
+		require.NoError(t, err)
+		logger.BeginTurn(4)
+		logger.LogEvent(EventPlayerMove, "p1", nil)
+		require.NoError(t, logger.Close())
+
+		reopened, err := NewFileEventLogger(dir, 1<<20)
+		require.NoError(t, err)
+		defer reopened.Close()
+		reopened.LogEvent(EventPlayerMove, "p1", nil)
+
+		events := reopened.GetPlayerEvents("p1", EventFilters{LastTurns: 1})
+		require.Len(t, events, 2)
+		assert.Equal(t, int64(4), events[1].Turn)
+	})
+
+	t.Run("restored game continues counting turns", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.endTurn()
+
+		restored, err := RestoreGame(roundTripSnapshot(t, ts.game.snapshot()))
+		require.NoError(t, err)
+		restored.endTurn()
+
+		assert.Equal(t, int64(2), restored.state.getTurn())
+	})
+}
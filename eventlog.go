@@ -45,6 +45,18 @@ const (
 	EventCardDrawn EventType = "card_drawn"
 	// EventCardDiscarded is triggered when a player discards a card
 	EventCardDiscarded EventType = "card_discarded"
+	// EventPhaseCompleted is triggered after each phase of a tick
+	EventPhaseCompleted EventType = "phase_completed"
+	// EventTradeOffered is triggered when a player offers another player a trade
+	EventTradeOffered EventType = "trade_offered"
+	// EventTradeAccepted is triggered when a player accepts a trade offer
+	EventTradeAccepted EventType = "trade_accepted"
+	// EventTradeCompleted is triggered when the trade phase swaps the cards of an accepted offer
+	EventTradeCompleted EventType = "trade_completed"
+	// EventTradeFailed is triggered when an offer is cancelled or cannot be carried out
+	EventTradeFailed EventType = "trade_failed"
+	// EventAdminAction is triggered when an admin intervenes in the game
+	EventAdminAction EventType = "admin_action"
 )
 
 // EventTypeList returns all valid event types
@@ -56,6 +68,13 @@ func EventTypeList() []EventType {
 		EventCombatResult,
 		EventResourceGained,
 		EventGameTick,
+		EventCardDiscarded,
+		EventPhaseCompleted,
+		EventTradeOffered,
+		EventTradeAccepted,
+		EventTradeCompleted,
+		EventTradeFailed,
+		EventAdminAction,
 	}
 }
 
@@ -87,6 +106,8 @@ type EventLogger interface {
 	GetEventCount() int64
 	// GetEventTypeCount returns the number of events of a specific type
 	GetEventTypeCount(eventType EventType) int64
+	// BeginTurn marks the start of turn n; events logged from now on belong to it
+	BeginTurn(n int64)
 }
 
 // EventLoggerImpl is the in-memory implementation of EventLogger
@@ -100,6 +121,10 @@ type EventLoggerImpl struct {
 
 // NewEventLogger creates a new instance of EventLogger
 func NewEventLogger() EventLogger {
+	return newEventLoggerImpl()
+}
+
+func newEventLoggerImpl() *EventLoggerImpl {
 	// Initialize event counts
 	eventCounts := make(map[EventType]*int64)
 	for _, et := range EventTypeList() {
@@ -123,6 +148,11 @@ func reverseSlice(events []GameEvent) {
 
 // LogEvent adds a new event to the log
 func (el *EventLoggerImpl) LogEvent(eventType EventType, playerID string, details map[string]interface{}) {
+	el.record(eventType, playerID, details)
+}
+
+// record creates a new event for the current turn, stores it and returns it
+func (el *EventLoggerImpl) record(eventType EventType, playerID string, details map[string]interface{}) GameEvent {
 	el.mu.Lock()
 	defer el.mu.Unlock()
 
@@ -134,23 +164,50 @@ func (el *EventLoggerImpl) LogEvent(eventType EventType, playerID string, detail
 		Details:   details,
 		Turn:      el.currentTurn,
 	}
+	el.appendEvent(event)
+	return event
+}
 
+// appendEvent stores an event and updates the counters. The caller holds el.mu.
+func (el *EventLoggerImpl) appendEvent(event GameEvent) {
 	el.events = append(el.events, event)
 	atomic.AddInt64(&el.totalEvents, 1)
-	if count, exists := el.eventCounts[eventType]; exists {
+	if count, exists := el.eventCounts[event.Type]; exists {
 		atomic.AddInt64(count, 1)
 	}
 }
 
+// BeginTurn moves the log to turn n. Events logged between two ticks belong
+// to the turn that ended last, events before the first tick to turn 0.
+func (el *EventLoggerImpl) BeginTurn(n int64) {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+	el.currentTurn = n
+}
+
+// restore stores events read back from persistent storage and moves the
+// current turn to the latest one among them
+func (el *EventLoggerImpl) restore(events []GameEvent) {
+	el.mu.Lock()
+	defer el.mu.Unlock()
+
+	for _, event := range events {
+		el.appendEvent(event)
+		if event.Turn > el.currentTurn {
+			el.currentTurn = event.Turn
+		}
+	}
+}
+
 // GetPlayerEvents returns all events visible to the specified player
 func (e *EventLoggerImpl) GetPlayerEvents(playerID string, filters EventFilters) []GameEvent {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	// If LastTurns is set, find the turn number N turns ago
+	// If LastTurns is set, find the oldest of the last N turns, the current one included
 	var minTurn int64 = 0
-	if filters.LastTurns > 0 && e.currentTurn > int64(filters.LastTurns) {
-		minTurn = e.currentTurn - int64(filters.LastTurns)
+	if filters.LastTurns > 0 && e.currentTurn >= int64(filters.LastTurns) {
+		minTurn = e.currentTurn - int64(filters.LastTurns) + 1
 	}
 
 	var result []GameEvent
@@ -172,8 +229,8 @@ func (e *EventLoggerImpl) GetPlayerEvents(playerID string, filters EventFilters)
 			// Only show player's own actions
 			visible = event.PlayerID == playerID
 
-		case EventPlayerDeath:
-			// Show all player deaths
+		case EventPlayerDeath, EventGameTick:
+			// Show all player deaths and tick summaries
 			visible = true
 
 		case EventCombatResult:
@@ -198,6 +255,10 @@ func (e *EventLoggerImpl) GetPlayerEvents(playerID string, filters EventFilters)
 				visible = true
 			}
 
+		case EventTradeOffered, EventTradeAccepted, EventTradeCompleted, EventTradeFailed:
+			// Show trades to both sides
+			visible = event.Details["from"] == playerID || event.Details["to"] == playerID
+
 		default:
 			// For any other event type, only show if it's the player's own event
 			visible = event.PlayerID == playerID
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Event log backends selectable through Config.EventLog.Backend
+const (
+	EventLogBackendMemory = "memory"
+	EventLogBackendFile   = "file"
+)
+
+// eventFilePattern names the segments of a file event log, oldest first
+const eventFilePattern = "events-%06d.jsonl"
+
+// newGameEventLogger creates the event logger configured for the game with gameID.
+// A file log is replayed only for a game restored from a snapshot; a new game
+// moves the log of an earlier game with the same ID aside and starts empty.
+func newGameEventLogger(gameID string, config *Config, restore bool) (EventLogger, error) {
+	if config.EventLog.Backend == EventLogBackendFile {
+		dir := filepath.Join(config.EventLog.Directory, gameID)
+		if !restore {
+			if err := archiveEventLog(dir); err != nil {
+				return nil, err
+			}
+		}
+		return NewFileEventLogger(dir, config.EventLog.MaxFileBytes)
+	}
+	return NewEventLogger(), nil
+}
+
+// archiveEventLog renames the event log in dir to <dir>.archived-<time> if it
+// has any segments, so that its events are kept but not replayed
+func archiveEventLog(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "events-*.jsonl"))
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	archive := fmt.Sprintf("%s.archived-%d", dir, time.Now().UnixNano())
+	if err := os.Rename(dir, archive); err != nil {
+		return fmt.Errorf("archiving event log: %w", err)
+	}
+	return nil
+}
+
+// FileEventLogger is an EventLogger that appends every event as one JSON line to
+// files on local disk. Queries are answered from memory like EventLoggerImpl;
+// on creation all existing segments are replayed, so the log survives restarts.
+type FileEventLogger struct {
+	*EventLoggerImpl
+	fileMu       sync.Mutex
+	dir          string
+	maxFileBytes int64
+	file         *os.File
+	fileIndex    int
+	fileSize     int64
+}
+
+// NewFileEventLogger opens the event log stored in dir, replaying existing
+// segments. A new segment is started once the current one exceeds maxFileBytes.
+func NewFileEventLogger(dir string, maxFileBytes int64) (*FileEventLogger, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating event log directory: %w", err)
+	}
+
+	l := &FileEventLogger{
+		EventLoggerImpl: newEventLoggerImpl(),
+		dir:             dir,
+		maxFileBytes:    maxFileBytes,
+	}
+	if err := l.replay(); err != nil {
+		return nil, err
+	}
+	if err := l.openSegment(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// LogEvent stores the event in memory and appends it to the current segment
+func (l *FileEventLogger) LogEvent(eventType EventType, playerID string, details map[string]interface{}) {
+	l.fileMu.Lock()
+	defer l.fileMu.Unlock()
+
+	event := l.EventLoggerImpl.record(eventType, playerID, details)
+	if err := l.write(event); err != nil {
+		log.Printf("Failed to persist event %s: %v", event.ID, err)
+	}
+}
+
+// Close closes the current segment
+func (l *FileEventLogger) Close() error {
+	l.fileMu.Lock()
+	defer l.fileMu.Unlock()
+	return l.file.Close()
+}
+
+// write appends one event line, rotating first if the segment is full.
+// The caller holds l.fileMu.
+func (l *FileEventLogger) write(event GameEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if l.fileSize > 0 && l.fileSize+int64(len(line)) > l.maxFileBytes {
+		if err := l.file.Close(); err != nil {
+			return err
+		}
+		l.fileIndex++
+		if err := l.openSegment(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.fileSize += int64(n)
+	return err
+}
+
+// openSegment opens the segment at l.fileIndex for appending
+func (l *FileEventLogger) openSegment() error {
+	path := filepath.Join(l.dir, fmt.Sprintf(eventFilePattern, l.fileIndex))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening event log segment: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file = file
+	l.fileSize = info.Size()
+	return nil
+}
+
+// replay loads all existing segments in order and continues with the last one
+func (l *FileEventLogger) replay() error {
+	paths, err := filepath.Glob(filepath.Join(l.dir, "events-*.jsonl"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	var events []GameEvent
+	for _, path := range paths {
+		segment, err := readEventSegment(path)
+		if err != nil {
+			return err
+		}
+		events = append(events, segment...)
+
+		var index int
+		if _, err := fmt.Sscanf(filepath.Base(path), eventFilePattern, &index); err == nil && index > l.fileIndex {
+			l.fileIndex = index
+		}
+	}
+	l.EventLoggerImpl.restore(events)
+	return nil
+}
+
+func readEventSegment(path string) ([]GameEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []GameEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event GameEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			// A crash can leave a partial last line behind; skip it rather than refusing to start
+			log.Printf("Skipping unreadable event in %s line %d: %v", path, line, err)
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
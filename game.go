@@ -0,0 +1,362 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultGameID is the game served by the routes without a /games/:gameId prefix
+const defaultGameID = "default"
+
+// Game owns the map, players, state, config and event log of one running game.
+// Several games can run side by side in one server process.
+//
+// mu serializes everything touching the game: the loop holds it for the timer and
+// the whole tick, API handlers hold it while reading or writing players and tiles.
+// Within a tick, combat runs one goroutine per occupied tile; those only touch their
+// own tile and players and use their own RNG, and the event log has its own lock.
+type Game struct {
+	mu     sync.RWMutex
+	ID     string
+	config *Config
+	rng    *RNG
+	gMap   gameMap
+	pMap   playerMap
+	state  gameState
+	events EventLogger
+	ticks  tickBroadcaster
+	trades tradeBook
+	quiet  bool // suppresses the progress output of the loop, used by simulations
+	wake   chan struct{}
+
+	beforePhase []PhaseHook
+	afterPhase  []PhaseHook
+}
+
+// NewGame creates a game from config. The config is owned by the game afterwards,
+// so callers sharing a config between games should pass a clone.
+func NewGame(id string, config *Config) (*Game, error) {
+	if config.Server.IDSalt == "" {
+		config.Server.IDSalt = newIDSalt()
+	}
+	events, err := newGameEventLogger(id, config, false)
+	if err != nil {
+		return nil, fmt.Errorf("creating event log for game %s: %w", id, err)
+	}
+	g := &Game{
+		ID:     id,
+		config: config,
+		rng:    NewRNG(config.Game.Seed),
+		events: events,
+		wake:   make(chan struct{}, 1),
+	}
+	g.gMap, err = NewGameMap(g)
+	if err != nil {
+		return nil, err
+	}
+	g.pMap = NewPlayerMap(g)
+	g.state = NewGameState(config, g.rng.Seed())
+	g.spawnBots()
+	return g, nil
+}
+
+// spawnBots adds the configured number of bots at game start
+func (g *Game) spawnBots() {
+	for i := 0; i < g.config.Game.BotNumber; i++ {
+		g.pMap.addBot(botName(i+1), botStrategyFor(g.config, i), g.gMap.getNewPlayerEntryTile())
+	}
+}
+
+func (g *Game) rollDice(dice *RNG, playerID string) int {
+	minAttack, maxAttack := g.config.Combat.PlayerMinAttack, g.config.Combat.PlayerMaxAttack
+	result := dice.Intn(maxAttack-minAttack+1) + minAttack
+
+	// Log the dice roll event
+	g.events.LogEvent(EventDiceRoll, playerID, map[string]interface{}{
+		"result": result,
+		"min":    minAttack,
+		"max":    maxAttack,
+	})
+
+	return result
+}
+
+// tick runs the configured phases in order
+func (g *Game) tick() {
+	g.logln("# Tick", g.ID)
+	for _, name := range g.phaseOrder() {
+		phase, exists := lookupPhase(name)
+		if !exists {
+			log.Printf("Skipping unknown phase %q in game %s", name, g.ID)
+			continue
+		}
+		g.runPhase(phase)
+	}
+}
+
+// phaseOrder returns the configured phases, or DefaultPhases if none are configured
+func (g *Game) phaseOrder() []string {
+	if len(g.config.Game.Phases) == 0 {
+		return DefaultPhases()
+	}
+	return g.config.Game.Phases
+}
+
+// logln prints the progress of the game unless it is quiet
+func (g *Game) logln(a ...interface{}) {
+	if !g.quiet {
+		fmt.Println(a...)
+	}
+}
+
+func (g *Game) getPlayerOrNil(id string) *Player {
+	return g.pMap.Players[id] //TODO: Improve
+}
+
+// playerSurroundings returns what the player sees around its tile
+func (g *Game) playerSurroundings(player Player) Surroundings {
+	// Special case: if player is dead, return laboratory surrounded by edges
+	if !player.Alive {
+		return Surroundings{
+			NW: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
+			NN: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
+			NE: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
+			WW: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
+			CE: MapPiece{Laboratory.toString(), 0, 0, 0, 0, 0, 0},
+			EE: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
+			SW: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
+			SS: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
+			SE: MapPiece{Edge.toString(), 0, 0, 0, 0, 0, 0},
+		}
+	}
+
+	var xPos = player.CurrentTile.XPos
+	var yPos = player.CurrentTile.YPos
+	return g.gMap.getSurroundingsFromPos(xPos, yPos)
+}
+
+// run counts down the turn timer and ticks the game until the players have won
+// or an admin ends the game. While the game is paused the timer stands still.
+func (g *Game) run() {
+	g.mu.RLock()
+	fmt.Println("Remaining turns: ", g.state.getRemainingTurns())
+	g.mu.RUnlock()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for !g.isFinished() {
+		if g.endTurnIf(g.turnCanEnd) {
+			continue
+		}
+		select {
+		case <-ticker.C:
+			g.timerDown()
+		case <-g.wake:
+		}
+	}
+}
+
+// wakeLoop makes the loop check right away whether the turn can end
+func (g *Game) wakeLoop() {
+	select {
+	case g.wake <- struct{}{}:
+	default:
+	}
+}
+
+// canEndTurn is true once the turn timer ran out, or early once every living
+// human player is ready and MinTurnLength has passed
+func (g *Game) canEndTurn() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.turnCanEnd()
+}
+
+// turnCanEnd is canEndTurn for callers holding g.mu
+func (g *Game) turnCanEnd() bool {
+	if g.state.paused || g.state.isFinished() {
+		return false
+	}
+	if g.state.isTurnOver() {
+		return true
+	}
+	return g.pMap.allHumansReady() && g.state.elapsed() >= g.config.Game.MinTurnLength
+}
+
+func (g *Game) isFinished() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.state.isFinished()
+}
+
+func (g *Game) timerDown() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.state.paused {
+		g.state.timerDown()
+	}
+}
+
+// endTurn resets the timer and resolves the tick while holding the game lock
+func (g *Game) endTurn() {
+	g.endTurnIf(func() bool { return true })
+}
+
+// endTurnIf ends the turn if ready returns true. ready runs under the game lock
+// in the same critical section as the tick, so nothing can end the game or
+// tick it in between. It reports whether the turn ended.
+func (g *Game) endTurnIf(ready func() bool) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !ready() {
+		return false
+	}
+	g.state.resetTime()
+	turn := g.state.nextTurn()
+	g.events.BeginTurn(turn)
+	g.ticks.publish(g.tickNotice(TickStarted))
+	g.tick()
+	g.pMap.rememberVisibleTiles()
+	g.pMap.resetReady()
+	g.logln("Remaining turns: ", g.state.getRemainingTurns())
+	if g.pMap.havePlayersWon() {
+		g.logln("Game over due to win")
+		g.state.win()
+	}
+	g.logTickSummary()
+	if g.config.Server.Verbose && !g.quiet {
+		if err := g.gMap.render(os.Stdout, MapWindow{}, false); err != nil {
+			log.Printf("Failed to render map of game %s: %v", g.ID, err)
+		}
+	}
+	g.saveSnapshot()
+	g.ticks.publish(g.tickNotice(TickFinished))
+	return true
+}
+
+// logTickSummary logs an EventGameTick describing the game after the tick
+func (g *Game) logTickSummary() {
+	alive := 0
+	for _, player := range g.pMap.Players {
+		if player.Alive {
+			alive++
+		}
+	}
+	g.events.LogEvent(EventGameTick, "", map[string]interface{}{
+		"turn":            g.state.getTurn(),
+		"remaining_turns": g.state.getRemainingTurns(),
+		"players":         len(g.pMap.Players),
+		"players_alive":   alive,
+		"zombies":         g.gMap.zombieTotal(),
+		"have_won":        g.state.haveWon(),
+	})
+}
+
+// GameSummary is the public overview of a game listed by GET /games
+type GameSummary struct {
+	ID             string
+	Players        int
+	TurnLength     int
+	TurnTime       int
+	RemainingTurns int
+	HaveWon        bool
+	Paused         bool
+	Ended          bool
+	Seed           int64
+	MapSeed        int64
+}
+
+func (g *Game) summary() GameSummary {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return GameSummary{
+		ID:             g.ID,
+		Players:        len(g.pMap.Players),
+		TurnLength:     g.config.Game.TurnLength,
+		TurnTime:       g.state.turnTimer,
+		RemainingTurns: g.state.getRemainingTurns(),
+		HaveWon:        g.state.haveWon(),
+		Paused:         g.state.paused,
+		Ended:          g.state.ended,
+		Seed:           g.state.getSeed(),
+		MapSeed:        g.config.Map.Seed,
+	}
+}
+
+// errGameExists is returned when creating or restoring a game whose ID is taken
+var errGameExists = errors.New("game already exists")
+
+// GameRegistry keeps track of all games running in this process
+type GameRegistry struct {
+	mu      sync.RWMutex
+	games   map[string]*Game
+	pending map[string]bool // IDs of games being built, reserved against duplicates
+}
+
+func NewGameRegistry() *GameRegistry {
+	return &GameRegistry{games: make(map[string]*Game), pending: make(map[string]bool)}
+}
+
+// create registers a new game; the caller starts its loop. An empty id generates one.
+func (r *GameRegistry) create(id string, config *Config) (*Game, error) {
+	if id == "" {
+		id = uuid.New().String()
+	}
+	return r.register(id, func() (*Game, error) { return NewGame(id, config) })
+}
+
+// restore registers a game rebuilt from a snapshot; the caller starts its loop.
+func (r *GameRegistry) restore(s *Snapshot) (*Game, error) {
+	return r.register(s.GameID, func() (*Game, error) { return RestoreGame(s) })
+}
+
+// register builds the game with the given ID and adds it to the registry. The
+// ID is reserved while build runs without the registry lock, so building a
+// large map or replaying a log does not hold up requests to other games, and
+// no second game with that ID can be built or registered meanwhile.
+func (r *GameRegistry) register(id string, build func() (*Game, error)) (*Game, error) {
+	r.mu.Lock()
+	if _, exists := r.games[id]; exists || r.pending[id] {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", errGameExists, id)
+	}
+	r.pending[id] = true
+	r.mu.Unlock()
+
+	game, err := build()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, id)
+	if err != nil {
+		return nil, err
+	}
+	r.games[id] = game
+	return game, nil
+}
+
+func (r *GameRegistry) get(id string) *Game {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.games[id]
+}
+
+// list returns all games ordered by ID
+func (r *GameRegistry) list() []*Game {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	games := make([]*Game, 0, len(r.games))
+	for _, game := range r.games {
+		games = append(games, game)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].ID < games[j].ID
+	})
+	return games
+}
@@ -1,39 +1,76 @@
 package main
 
 import (
-	"math/rand"
 	"sync"
 )
 
 type gameMap struct {
+	game   *Game
 	gMap   [][]*Tile
 	width  int
 	height int
+	spawns [][2]int // tiles new players enter on, empty for random tiles
 }
 
-func NewGameMap() gameMap {
-	width := gameConfig.Map.Width
-	height := gameConfig.Map.Height
-	
+// NewGameMap loads the map file configured in Map.File, or generates a map
+func NewGameMap(game *Game) (gameMap, error) {
+	if game.config.Map.File != "" {
+		m, err := LoadMapFile(game.config.Map.File)
+		if err != nil {
+			return gameMap{}, err
+		}
+		return newGameMapFromFile(game, m), nil
+	}
+
+	instance := newEmptyGameMap(game, game.config.Map.Width, game.config.Map.Height)
+	instance.init()
+	return instance, nil
+}
+
+func newEmptyGameMap(game *Game, width, height int) gameMap {
 	instance := gameMap{
+		game:   game,
 		width:  width,
 		height: height,
 		gMap:   make([][]*Tile, width),
 	}
-	
 	for i := range instance.gMap {
 		instance.gMap[i] = make([]*Tile, height)
 	}
-	
-	instance.init()
 	return instance
 }
 
+// newGameMapFromFile builds the map described by a map file. The game's
+// Map.Width and Map.Height are set to the size of the file's map.
+func newGameMapFromFile(game *Game, m *MapFile) gameMap {
+	game.config.Map.Width, game.config.Map.Height = m.Width, m.Height
+	instance := newEmptyGameMap(game, m.Width, m.Height)
+	for a, column := range instance.gMap {
+		for b := range column {
+			instance.gMap[a][b] = &Tile{m.Terrain[a][b], m.Zombies[[2]int{a, b}], []*Player{}, a, b, game, nil}
+		}
+	}
+	instance.spawns = append([][2]int(nil), m.Spawns...)
+	return instance
+}
+
+// init generates the terrain with the configured generator. Without a map seed
+// one is drawn from the game RNG and recorded in the config, so the map can be
+// generated again independently of the game seed.
 func (g *gameMap) init() {
+	config := g.game.config
+	for config.Map.Seed == 0 {
+		config.Map.Seed = g.game.rng.Int63()
+	}
+	generator, exists := mapGenerators[config.Map.Generator]
+	if !exists {
+		generator = mapGenerators[MapGeneratorUniform]
+	}
+	terrain := generator.Generate(g.width, g.height, config, NewRNG(config.Map.Seed))
+
 	for a, column := range g.gMap {
 		for b := range column {
-			choice := rand.Intn(len(terrainTypes) - 1)
-			g.gMap[a][b] = &Tile{terrainTypes[choice], 0, []*Player{}, a, b}
+			g.gMap[a][b] = &Tile{terrain[a][b], 0, []*Player{}, a, b, g.game, nil}
 		}
 	}
 }
@@ -42,11 +79,23 @@ func (g gameMap) handleCombat() {
 	var wg = sync.WaitGroup{}
 	for x, _ := range g.gMap {
 		for y, _ := range g.gMap[x] {
+			tile := g.gMap[x][y]
+			if len(tile.playerPtrs) == 0 {
+				continue
+			}
+			// Tiles fight concurrently, so each gets its own generator drawn in map order
 			wg.Add(1)
-			go tileWorker(g.gMap[x][y], &wg)
+			go tileWorker(tile, g.game.rng.Derive(), &wg)
 		}
 	}
 	wg.Wait()
+
+	// Retreating players leave only after every tile fought, as the tiles fight concurrently
+	for x := range g.gMap {
+		for _, tile := range g.gMap[x] {
+			tile.withdrawRetreats()
+		}
+	}
 }
 
 func (g gameMap) resources() {
@@ -57,10 +106,21 @@ func (g gameMap) resources() {
 	}
 }
 
+// zombieTotal counts the zombies on all tiles of the map
+func (g gameMap) zombieTotal() int {
+	total := 0
+	for x := range g.gMap {
+		for _, tile := range g.gMap[x] {
+			total += tile.Zombies
+		}
+	}
+	return total
+}
+
 // getTile safely retrieves a tile, returning an Edge tile for out-of-bounds requests.
 func (g gameMap) getTile(x, y int) *Tile {
 	if x < 0 || x >= g.width || y < 0 || y >= g.height {
-		return &Tile{Terrain: Edge, XPos: x, YPos: y} // Return a default edge tile
+		return &Tile{Terrain: Edge, XPos: x, YPos: y, game: g.game} // Return a default edge tile
 	}
 	return g.gMap[x][y]
 }
@@ -119,6 +179,21 @@ func (g gameMap) addZombiesToTile(xPos int, yPos int, count int) {
 	g.getTile(xPos, yPos).addZombiesUnbound(count)
 }
 
+// setZombiesOnTile sets the number of zombies on a tile, ignoring ZombieCutoff
+func (g gameMap) setZombiesOnTile(xPos int, yPos int, count int) {
+	current := g.getTile(xPos, yPos).Zombies
+	if count > current {
+		g.addZombiesToTile(xPos, yPos, count-current)
+	} else {
+		g.removeZombiesFromTile(xPos, yPos, current-count)
+	}
+}
+
+// isOnMap is true if x, y is a tile of the map and not beyond its edge
+func (g gameMap) isOnMap(x, y int) bool {
+	return x >= 0 && x < g.width && y >= 0 && y < g.height
+}
+
 func (g *gameMap) spread() {
 	for x, _ := range g.gMap {
 		for y, tile := range g.gMap[x] {
@@ -130,17 +205,20 @@ func (g *gameMap) spread() {
 }
 
 func (g gameMap) getNewPlayerEntryTile() *Tile {
+	if len(g.spawns) > 0 {
+		return g.getSpawnTile()
+	}
     // Prefer spawning on an empty tile and ensure the spawn tile is safe (no zombies)
     // 1) Try random sampling up to the total number of tiles
     total := g.width * g.height
     if total <= 0 {
         // Fallback: return a safe edge tile if the map is somehow invalid
-        return &Tile{Terrain: Edge, XPos: 0, YPos: 0}
+        return &Tile{Terrain: Edge, XPos: 0, YPos: 0, game: g.game}
     }
 
     for i := 0; i < total; i++ {
-        rX := r.Intn(g.width)  // include full range [0, width-1]
-        rY := r.Intn(g.height) // include full range [0, height-1]
+        rX := g.game.rng.Intn(g.width)  // include full range [0, width-1]
+        rY := g.game.rng.Intn(g.height) // include full range [0, height-1]
         tile := g.gMap[rX][rY]
         if tile != nil && len(tile.playerPtrs) == 0 {
             if tile.Zombies > 0 {
@@ -183,5 +261,22 @@ func (g gameMap) getNewPlayerEntryTile() *Tile {
     }
 
     // 4) Ultimate fallback (should not happen with a valid map)
-    return &Tile{Terrain: Edge, XPos: 0, YPos: 0}
+    return &Tile{Terrain: Edge, XPos: 0, YPos: 0, game: g.game}
+}
+
+// getSpawnTile returns an empty spawn point, starting the search at a random one,
+// or a random spawn point if all are taken. Like other entry tiles it is cleared
+// of zombies.
+func (g gameMap) getSpawnTile() *Tile {
+	start := g.game.rng.Intn(len(g.spawns))
+	tile := g.getTileFromPos(g.spawns[start][0], g.spawns[start][1])
+	for i := range g.spawns {
+		spawn := g.spawns[(start+i)%len(g.spawns)]
+		if candidate := g.getTileFromPos(spawn[0], spawn[1]); len(candidate.playerPtrs) == 0 {
+			tile = candidate
+			break
+		}
+	}
+	tile.removeZombies(tile.Zombies)
+	return tile
 }
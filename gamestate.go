@@ -1,13 +1,19 @@
 package main
 
 type gameState struct {
+	config         *Config
 	turnTimer      int
+	turnElapsed    int // seconds since the turn started, kept apart as TurnLength may change mid-turn
 	remainingTurns int
 	havePlayersWon bool
+	seed           int64
+	turn           int64 // number of the last tick, 0 before the first one
+	paused         bool  // the turn timer stands still
+	ended          bool  // ended by an admin
 }
 
-func NewGameState() gameState {
-	return gameState{gameConfig.Game.TurnLength, gameConfig.Game.MaxTurns, false}
+func NewGameState(config *Config, seed int64) gameState {
+	return gameState{config: config, turnTimer: config.Game.TurnLength, remainingTurns: config.Game.MaxTurns, seed: seed}
 }
 
 func (gs gameState) haveWon() bool {
@@ -18,8 +24,18 @@ func (gs *gameState) win() {
 	gs.havePlayersWon = true
 }
 
+func (gs *gameState) end() {
+	gs.ended = true
+}
+
+// isFinished is true once the game loop stops: the players have won or an admin ended the game
+func (gs gameState) isFinished() bool {
+	return gs.havePlayersWon || gs.ended
+}
+
 func (gs *gameState) timerDown() {
 	gs.turnTimer--
+	gs.turnElapsed++
 }
 
 func (gs gameState) isTurnOver() bool {
@@ -29,17 +45,37 @@ func (gs gameState) isTurnOver() bool {
 	return false
 }
 
+// elapsed returns the seconds that passed since the turn started
+func (gs gameState) elapsed() int {
+	return gs.turnElapsed
+}
+
 func (gs *gameState) resetTime() {
-	gs.turnTimer = gameConfig.Game.TurnLength
+	gs.turnTimer = gs.config.Game.TurnLength
+	gs.turnElapsed = 0
 	gs.remainingTurns--
 }
 
+// nextTurn advances the turn counter and returns the new turn number
+func (gs *gameState) nextTurn() int64 {
+	gs.turn++
+	return gs.turn
+}
+
+func (gs gameState) getTurn() int64 {
+	return gs.turn
+}
+
 func (gs gameState) getRemainingTurns() int {
 	return gs.remainingTurns
 }
 
+func (gs gameState) getSeed() int64 {
+	return gs.seed
+}
+
 func (gs gameState) isGameOver() bool {
-	if gs.remainingTurns < 0 || gs.haveWon() {
+	if gs.remainingTurns < 0 || gs.isFinished() {
 		return true
 	}
 	return false
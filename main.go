@@ -1,80 +1,60 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"math/rand"
-	"os"
-	"time"
+	"log"
 )
 
-var pMap playerMap
-var r *rand.Rand
-var gMap gameMap
-var gState gameState
-
-// eventLogger is the global event logger instance
-var eventLogger EventLogger
-
-func rollDice(playerID string) int {
-	result := rand.Intn(gameConfig.Combat.PlayerMaxAttack) + gameConfig.Combat.PlayerMinAttack
-
-	// Log the dice roll event
-	eventLogger.LogEvent(EventDiceRoll, playerID, map[string]interface{}{
-		"result": result,
-		"min":    gameConfig.Combat.PlayerMinAttack,
-		"max":    gameConfig.Combat.PlayerMaxAttack,
-	})
-
-	return result
-}
-
-func tick() {
-	fmt.Println("# Tick")
-	fmt.Println("Moving players...")
-	pMap.move()
-	fmt.Println("Distributing ressources...")
-	gMap.resources()
-	fmt.Println("Combat is upon us...")
-	gMap.handleCombat()
-	fmt.Println("The infection is spreading...")
-	gMap.spread()
-	fmt.Println("Players feeding themselves...")
-	pMap.playersConsume()
-	fmt.Println("Limiting player inventory")
-	pMap.limitCards()
-}
-
-func getPlayerOrNil(id string) *Player {
-	return pMap.Players[id] //TODO: Improve
-}
-
 func main() {
-	if len(os.Args) == 2 {
-		gameConfig.Server.IDSalt = os.Args[1]
-		fmt.Println(gameConfig.Server.IDSalt)
+	configPath := flag.String("config", "", "path to a JSON or YAML file overriding the default game config")
+	restorePath := flag.String("restore", "", "path to a game snapshot to resume")
+	verbose := flag.Bool("verbose", false, "print the map after every tick")
+	flag.Parse()
+
+	if *configPath != "" {
+		config, err := LoadConfig(*configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		gameConfig = config
+	}
+	if *verbose {
+		gameConfig.Server.Verbose = true
+	}
+	if flag.NArg() > 0 && flag.Arg(0) == "simulate" {
+		if err := runSimulateCommand(flag.Args()[1:], gameConfig); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if flag.NArg() == 1 {
+		gameConfig.Server.IDSalt = flag.Arg(0)
+		fmt.Println("Using ID salt from the command line")
 	}
 
-	r = rand.New(rand.NewSource(time.Now().Unix()))
-	gMap = NewGameMap()
-	gState = NewGameState()
-	pMap = NewPlayerMap()
-	eventLogger = NewEventLogger() // Initialize the global event logger
-
-	go setupAPI()
+	registry := NewGameRegistry()
+	if *restorePath != "" {
+		snapshot, err := LoadSnapshot(*restorePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		game, err := registry.restore(snapshot)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Restored game", game.ID, "with", game.state.getRemainingTurns(), "remaining turns")
+		go game.run()
+	}
 
-	fmt.Println("Remaining turns: ", gState.getRemainingTurns())
-	for !gState.haveWon() {
-		if !gState.isTurnOver() {
-			time.Sleep(time.Second)
-			gState.timerDown()
-		} else {
-			gState.resetTime()
-			tick()
-			fmt.Println("Remaining turns: ", gState.getRemainingTurns())
-			if pMap.havePlayersWon() {
-				fmt.Println("Game over due to win")
-				gState.win()
-			}
+	if registry.get(defaultGameID) == nil {
+		game, err := registry.create(defaultGameID, gameConfig.clone())
+		if err != nil {
+			log.Fatal(err)
 		}
+		fmt.Println("Seed: ", game.rng.Seed())
+		go game.run()
 	}
+
+	setupAPI(registry)
 }
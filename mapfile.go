@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A map file describes a board as a character grid, one line per row from north
+// to south, one character per tile from west to east:
+//
+//	T forest   F farm   C city   L laboratory
+//
+// The emoji of Terrain.toChar are accepted as well. Lines starting with # are
+// comments. Directive lines below or between the rows add details:
+//
+//	zombies <x> <y> <count>   initial zombies on a tile
+//	spawn <x> <y>             tile new players enter the map on
+//
+// Without spawn points players enter on random tiles.
+
+// mapFileLetters are the characters map files are written with
+var mapFileLetters = map[Terrain]rune{
+	Forest:     'T',
+	Farm:       'F',
+	City:       'C',
+	Laboratory: 'L',
+}
+
+// MapFile is a parsed map file. Terrain is indexed [x][y] like gameMap.
+type MapFile struct {
+	Width   int
+	Height  int
+	Terrain [][]Terrain
+	Zombies map[[2]int]int
+	Spawns  [][2]int
+}
+
+// terrainFromMapChar maps a grid character to its terrain
+func terrainFromMapChar(char rune) (Terrain, bool) {
+	for terrain, letter := range mapFileLetters {
+		if strings.EqualFold(string(char), string(letter)) || string(char) == terrain.toChar() {
+			return terrain, true
+		}
+	}
+	return Edge, false
+}
+
+// LoadMapFile reads and parses the map file at path
+func LoadMapFile(path string) (*MapFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening map %s: %w", path, err)
+	}
+	defer file.Close()
+	m, err := ParseMapFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("parsing map %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// ParseMapFile reads a map in the map file format
+func ParseMapFile(r io.Reader) (*MapFile, error) {
+	var rows [][]Terrain
+	m := &MapFile{Zombies: make(map[[2]int]int)}
+	type directive struct {
+		line   int
+		fields []string
+	}
+	var directives []directive
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		switch strings.ToLower(fields[0]) {
+		case "zombies", "spawn":
+			directives = append(directives, directive{line, fields})
+			continue
+		}
+
+		var row []Terrain
+		for _, char := range text {
+			terrain, ok := terrainFromMapChar(char)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown terrain %q", line, char)
+			}
+			row = append(row, terrain)
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("line %d: row has %d tiles, expected %d", line, len(row), len(rows[0]))
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("map has no rows")
+	}
+
+	m.Width, m.Height = len(rows[0]), len(rows)
+	m.Terrain = newTerrainGrid(m.Width, m.Height)
+	for y, row := range rows {
+		for x, terrain := range row {
+			m.Terrain[x][y] = terrain
+		}
+	}
+
+	for _, d := range directives {
+		keyword := strings.ToLower(d.fields[0])
+		expected := 3
+		if keyword == "zombies" {
+			expected = 4
+		}
+		if len(d.fields) != expected {
+			return nil, fmt.Errorf("line %d: %s takes %d numbers", d.line, keyword, expected-1)
+		}
+		numbers := make([]int, 0, expected-1)
+		for _, field := range d.fields[1:] {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("line %d: %q is not a non-negative number", d.line, field)
+			}
+			numbers = append(numbers, n)
+		}
+		pos := [2]int{numbers[0], numbers[1]}
+		if pos[0] >= m.Width || pos[1] >= m.Height {
+			return nil, fmt.Errorf("line %d: %d,%d is not on the %dx%d map", d.line, pos[0], pos[1], m.Width, m.Height)
+		}
+		if keyword == "zombies" {
+			m.Zombies[pos] = numbers[2]
+		} else {
+			m.Spawns = append(m.Spawns, pos)
+		}
+	}
+	return m, nil
+}
+
+// Write writes the map in the map file format
+func (m *MapFile) Write(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			out.WriteRune(mapFileLetters[m.Terrain[x][y]])
+		}
+		out.WriteByte('\n')
+	}
+	for x := 0; x < m.Width; x++ {
+		for y := 0; y < m.Height; y++ {
+			if zombies := m.Zombies[[2]int{x, y}]; zombies > 0 {
+				fmt.Fprintf(out, "zombies %d %d %d\n", x, y, zombies)
+			}
+		}
+	}
+	for _, spawn := range m.Spawns {
+		fmt.Fprintf(out, "spawn %d %d\n", spawn[0], spawn[1])
+	}
+	return out.Flush()
+}
+
+// mapFile captures the current terrain, zombies and spawn points of the map
+func (g gameMap) mapFile() *MapFile {
+	m := &MapFile{
+		Width:   g.width,
+		Height:  g.height,
+		Terrain: newTerrainGrid(g.width, g.height),
+		Zombies: make(map[[2]int]int),
+		Spawns:  append([][2]int(nil), g.spawns...),
+	}
+	for x := range g.gMap {
+		for y, tile := range g.gMap[x] {
+			m.Terrain[x][y] = tile.Terrain
+			if tile.Zombies > 0 {
+				m.Zombies[[2]int{x, y}] = tile.Zombies
+			}
+		}
+	}
+	return m
+}
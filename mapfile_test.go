@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMapFile = `# A small board
+TTFC
+tL🌱C
+CCFT
+
+zombies 3 0 2
+zombies 0 2 5
+spawn 1 0
+spawn 2 2
+`
+
+func TestMapFile(t *testing.T) {
+	writeMap := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "board.map")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	newGameWithMap := func(t *testing.T, path string) *Game {
+		config := gameConfig.clone()
+		config.Game.Seed = 1
+		config.Map.File = path
+		game, err := NewGame("board", config)
+		require.NoError(t, err)
+		return game
+	}
+
+	t.Run("grid, zombies and spawn points are parsed", func(t *testing.T) {
+		m, err := ParseMapFile(strings.NewReader(testMapFile))
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, m.Width)
+		assert.Equal(t, 3, m.Height)
+		assert.Equal(t, []Terrain{Forest, Forest, Farm, City}, []Terrain{m.Terrain[0][0], m.Terrain[1][0], m.Terrain[2][0], m.Terrain[3][0]})
+		assert.Equal(t, Laboratory, m.Terrain[1][1])
+		assert.Equal(t, Farm, m.Terrain[2][1], "Emoji should be accepted")
+		assert.Equal(t, map[[2]int]int{{3, 0}: 2, {0, 2}: 5}, m.Zombies)
+		assert.Equal(t, [][2]int{{1, 0}, {2, 2}}, m.Spawns)
+	})
+
+	t.Run("invalid files are rejected with their line", func(t *testing.T) {
+		for content, message := range map[string]string{
+			"TT\nTTT\n":            "line 2",
+			"TX\n":                 "unknown terrain",
+			"TT\nzombies 5 0 1\n":  "not on the",
+			"TT\nspawn 0\n":        "spawn takes 2",
+			"TT\nzombies 0 0 -1\n": "non-negative",
+			"# nothing\n":          "no rows",
+		} {
+			_, err := ParseMapFile(strings.NewReader(content))
+			assert.ErrorContains(t, err, message, content)
+		}
+	})
+
+	t.Run("game loads the configured map file", func(t *testing.T) {
+		game := newGameWithMap(t, writeMap(t, testMapFile))
+
+		assert.Equal(t, 4, game.gMap.width)
+		assert.Equal(t, 3, game.gMap.height)
+		assert.Equal(t, 4, game.config.Map.Width)
+		assert.Equal(t, Laboratory, game.gMap.getTileFromPos(1, 1).Terrain)
+		assert.Equal(t, 5, game.gMap.getTileFromPos(0, 2).Zombies)
+	})
+
+	t.Run("players enter on spawn points", func(t *testing.T) {
+		game := newGameWithMap(t, writeMap(t, testMapFile+"zombies 2 2 3\n"))
+
+		first := game.gMap.getNewPlayerEntryTile()
+		game.pMap.addPlayer("Alice", first)
+		second := game.gMap.getNewPlayerEntryTile()
+
+		spawns := [][2]int{{1, 0}, {2, 2}}
+		assert.Contains(t, spawns, [2]int{first.XPos, first.YPos})
+		assert.Contains(t, spawns, [2]int{second.XPos, second.YPos})
+		assert.NotSame(t, first, second, "Empty spawn points should be preferred")
+		assert.Zero(t, game.gMap.getTileFromPos(2, 2).Zombies, "Spawn points should be cleared of zombies")
+	})
+
+	t.Run("missing map file fails the game", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Map.File = filepath.Join(t.TempDir(), "missing.map")
+
+		_, err := NewGame("board", config)
+
+		assert.ErrorContains(t, err, "missing.map")
+	})
+
+	t.Run("exported map loads as the same map", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.setupTile(2, 3, City, 4)
+		ts.game.gMap.spawns = [][2]int{{1, 1}}
+
+		var out bytes.Buffer
+		require.NoError(t, ts.game.gMap.mapFile().Write(&out))
+		game := newGameWithMap(t, writeMap(t, out.String()))
+
+		assert.Equal(t, ts.game.gMap.mapFile(), game.gMap.mapFile())
+	})
+
+	t.Run("spawn points survive a snapshot", func(t *testing.T) {
+		game := newGameWithMap(t, writeMap(t, testMapFile))
+
+		restored, err := RestoreGame(roundTripSnapshot(t, game.snapshot()))
+		require.NoError(t, err)
+
+		assert.Equal(t, game.gMap.spawns, restored.gMap.spawns)
+	})
+
+	t.Run("admin endpoint exports the map", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+
+		rec := as.requestWithToken(http.MethodGet, "/admin/map", "", "secret")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		m, err := ParseMapFile(rec.Body)
+		require.NoError(t, err)
+		assert.Equal(t, game.gMap.mapFile().Terrain, m.Terrain)
+	})
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"sort"
+)
+
+// MapGenerator chooses the terrain of every tile of a new map, indexed [x][y].
+// Generators must draw all randomness from rng so a map seed reproduces the map.
+type MapGenerator interface {
+	Generate(width, height int, config *Config, rng *RNG) [][]Terrain
+}
+
+// Names of the built-in map generators
+const (
+	MapGeneratorUniform   = "uniform"
+	MapGeneratorClustered = "clustered"
+)
+
+// mapGenerators is the registry of generators selectable through Map.Generator
+var mapGenerators = map[string]MapGenerator{
+	MapGeneratorUniform:   uniformGenerator{},
+	MapGeneratorClustered: clusteredGenerator{smoothingSteps: 4},
+}
+
+// MapGeneratorNames returns the names of all registered generators in sorted order
+func MapGeneratorNames() []string {
+	names := make([]string, 0, len(mapGenerators))
+	for name := range mapGenerators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// uniformGenerator picks every tile independently, weighted by Map.TerrainWeights
+type uniformGenerator struct{}
+
+func (uniformGenerator) Generate(width, height int, config *Config, rng *RNG) [][]Terrain {
+	weights := config.Map.TerrainWeights
+	terrain := newTerrainGrid(width, height)
+	for x := range terrain {
+		for y := range terrain[x] {
+			terrain[x][y] = pickTerrain(weights, rng)
+		}
+	}
+	spaceLaboratories(terrain, config.Map.MinLabDistance, weights, rng)
+	return terrain
+}
+
+// clusteredGenerator grows contiguous forests, farmland and city districts with
+// a cellular automaton: it starts from uniform noise without laboratories and
+// lets every tile repeatedly take the most common terrain around it. Laboratories
+// are placed afterwards, Map.MinLabDistance apart. The smoothing shifts the
+// terrain ratios somewhat towards the more common terrains.
+type clusteredGenerator struct {
+	smoothingSteps int
+}
+
+func (c clusteredGenerator) Generate(width, height int, config *Config, rng *RNG) [][]Terrain {
+	weights := config.Map.TerrainWeights
+	landWeights := make(map[Terrain]int, len(weights))
+	for terrain, weight := range weights {
+		if terrain != Laboratory {
+			landWeights[terrain] = weight
+		}
+	}
+
+	terrain := newTerrainGrid(width, height)
+	for x := range terrain {
+		for y := range terrain[x] {
+			terrain[x][y] = pickTerrain(landWeights, rng)
+		}
+	}
+	for step := 0; step < c.smoothingSteps; step++ {
+		terrain = smoothTerrain(terrain)
+	}
+
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+	labs := 0
+	if total > 0 {
+		labs = (width*height*weights[Laboratory] + total/2) / total
+	}
+	placeLaboratories(terrain, labs, config.Map.MinLabDistance, rng)
+	return terrain
+}
+
+func newTerrainGrid(width, height int) [][]Terrain {
+	terrain := make([][]Terrain, width)
+	for x := range terrain {
+		terrain[x] = make([]Terrain, height)
+	}
+	return terrain
+}
+
+// pickTerrain draws a terrain with probability proportional to its weight
+func pickTerrain(weights map[Terrain]int, rng *RNG) Terrain {
+	total := 0
+	for _, terrain := range terrainTypes {
+		total += weights[terrain]
+	}
+	if total <= 0 {
+		return Forest
+	}
+	choice := rng.Intn(total)
+	for _, terrain := range terrainTypes {
+		if choice < weights[terrain] {
+			return terrain
+		}
+		choice -= weights[terrain]
+	}
+	return Forest
+}
+
+// smoothTerrain gives every tile the most common terrain of its 3x3 neighbourhood.
+// Ties keep the tile's own terrain if it is among them, otherwise the lowest terrain wins.
+func smoothTerrain(terrain [][]Terrain) [][]Terrain {
+	width, height := len(terrain), len(terrain[0])
+	smoothed := newTerrainGrid(width, height)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			var counts [len(terrainTypes)]int
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					nx, ny := x+dx, y+dy
+					if nx >= 0 && nx < width && ny >= 0 && ny < height {
+						counts[terrain[nx][ny]]++
+					}
+				}
+			}
+			best := terrain[x][y]
+			for _, candidate := range terrainTypes {
+				if counts[candidate] > counts[best] {
+					best = candidate
+				}
+			}
+			smoothed[x][y] = best
+		}
+	}
+	return smoothed
+}
+
+// labDistance is the number of steps between two tiles
+func labDistance(a, b [2]int) int {
+	dx, dy := a[0]-b[0], a[1]-b[1]
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return dx + dy
+}
+
+// farFromAll is true if pos is at least minDistance steps away from every lab in labs
+func farFromAll(pos [2]int, labs [][2]int, minDistance int) bool {
+	for _, lab := range labs {
+		if labDistance(pos, lab) < minDistance {
+			return false
+		}
+	}
+	return true
+}
+
+// spaceLaboratories turns laboratories closer than minDistance to an earlier one
+// into other terrain, drawn by weight
+func spaceLaboratories(terrain [][]Terrain, minDistance int, weights map[Terrain]int, rng *RNG) {
+	if minDistance <= 0 {
+		return
+	}
+	landWeights := make(map[Terrain]int, len(weights))
+	for t, weight := range weights {
+		if t != Laboratory {
+			landWeights[t] = weight
+		}
+	}
+
+	var kept [][2]int
+	for x := range terrain {
+		for y := range terrain[x] {
+			if terrain[x][y] != Laboratory {
+				continue
+			}
+			if farFromAll([2]int{x, y}, kept, minDistance) {
+				kept = append(kept, [2]int{x, y})
+			} else {
+				terrain[x][y] = pickTerrain(landWeights, rng)
+			}
+		}
+	}
+}
+
+// placeLaboratories puts up to count laboratories on random tiles at least
+// minDistance apart. Fewer are placed if the map has no room for more.
+func placeLaboratories(terrain [][]Terrain, count, minDistance int, rng *RNG) {
+	width, height := len(terrain), len(terrain[0])
+	var labs [][2]int
+	for attempt := 0; len(labs) < count && attempt < 20*width*height; attempt++ {
+		pos := [2]int{rng.Intn(width), rng.Intn(height)}
+		if terrain[pos[0]][pos[1]] == Laboratory || !farFromAll(pos, labs, minDistance) {
+			continue
+		}
+		terrain[pos[0]][pos[1]] = Laboratory
+		labs = append(labs, pos)
+	}
+}
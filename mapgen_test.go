@@ -0,0 +1,135 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapGenerators(t *testing.T) {
+	newMap := func(t *testing.T, configure func(config *Config)) *Game {
+		config := gameConfig.clone()
+		config.Game.Seed = 1
+		config.Map.Width = 40
+		config.Map.Height = 40
+		configure(config)
+		require.NoError(t, config.Validate())
+		game, err := NewGame("map", config)
+		require.NoError(t, err)
+		return game
+	}
+	countTerrain := func(game *Game) map[Terrain]int {
+		counts := map[Terrain]int{}
+		for x := range game.gMap.gMap {
+			for _, tile := range game.gMap.gMap[x] {
+				counts[tile.Terrain]++
+			}
+		}
+		return counts
+	}
+	// sameNeighbours is the share of horizontally or vertically adjacent tile pairs with equal terrain
+	sameNeighbours := func(game *Game) float64 {
+		same, pairs := 0, 0
+		for x := 0; x < game.gMap.width; x++ {
+			for y := 0; y < game.gMap.height; y++ {
+				if x+1 < game.gMap.width {
+					pairs++
+					if game.gMap.gMap[x][y].Terrain == game.gMap.gMap[x+1][y].Terrain {
+						same++
+					}
+				}
+				if y+1 < game.gMap.height {
+					pairs++
+					if game.gMap.gMap[x][y].Terrain == game.gMap.gMap[x][y+1].Terrain {
+						same++
+					}
+				}
+			}
+		}
+		return float64(same) / float64(pairs)
+	}
+	laboratories := func(game *Game) [][2]int {
+		var labs [][2]int
+		for x := range game.gMap.gMap {
+			for y, tile := range game.gMap.gMap[x] {
+				if tile.Terrain == Laboratory {
+					labs = append(labs, [2]int{x, y})
+				}
+			}
+		}
+		return labs
+	}
+
+	for _, generator := range MapGeneratorNames() {
+		t.Run(generator+" follows the terrain weights", func(t *testing.T) {
+			game := newMap(t, func(config *Config) {
+				config.Map.Generator = generator
+				config.Map.TerrainWeights = map[Terrain]int{Forest: 3, Farm: 1, City: 0, Laboratory: 0}
+			})
+
+			counts := countTerrain(game)
+
+			assert.Zero(t, counts[City])
+			assert.Zero(t, counts[Laboratory])
+			assert.Greater(t, counts[Forest], counts[Farm])
+		})
+
+		t.Run(generator+" keeps laboratories apart", func(t *testing.T) {
+			game := newMap(t, func(config *Config) {
+				config.Map.Generator = generator
+				config.Map.MinLabDistance = 6
+			})
+
+			labs := laboratories(game)
+
+			require.NotEmpty(t, labs)
+			for i := range labs {
+				for j := i + 1; j < len(labs); j++ {
+					assert.GreaterOrEqual(t, labDistance(labs[i], labs[j]), 6, "%v and %v", labs[i], labs[j])
+				}
+			}
+		})
+	}
+
+	t.Run("clustered maps are more contiguous than uniform ones", func(t *testing.T) {
+		uniform := newMap(t, func(config *Config) { config.Map.Generator = MapGeneratorUniform })
+		clustered := newMap(t, func(config *Config) { config.Map.Generator = MapGeneratorClustered })
+
+		assert.Greater(t, sameNeighbours(clustered), sameNeighbours(uniform)+0.2)
+		assert.NotEmpty(t, laboratories(clustered))
+	})
+
+	t.Run("map seed is recorded and reproduces the map", func(t *testing.T) {
+		first := newMap(t, func(config *Config) { config.Map.Generator = MapGeneratorClustered })
+		require.NotZero(t, first.config.Map.Seed)
+		assert.Equal(t, first.config.Map.Seed, first.summary().MapSeed)
+
+		second := newMap(t, func(config *Config) {
+			config.Game.Seed = 99
+			config.Map.Generator = MapGeneratorClustered
+			config.Map.Seed = first.config.Map.Seed
+		})
+
+		for x := range first.gMap.gMap {
+			for y := range first.gMap.gMap[x] {
+				require.Equal(t, first.gMap.gMap[x][y].Terrain, second.gMap.gMap[x][y].Terrain)
+			}
+		}
+	})
+
+	t.Run("invalid generator settings are config errors", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Map.Generator = "fractal"
+		config.Map.TerrainWeights = map[Terrain]int{Forest: -1, Edge: 1}
+		config.Map.MinLabDistance = -1
+
+		err := config.Validate()
+
+		assert.ErrorContains(t, err, "Map.Generator")
+		assert.ErrorContains(t, err, "Map.TerrainWeights.Forest")
+		assert.ErrorContains(t, err, "Map.TerrainWeights.Edge")
+		assert.ErrorContains(t, err, "Map.TerrainWeights: must give")
+		assert.ErrorContains(t, err, "Map.MinLabDistance")
+	})
+}
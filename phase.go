@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Phase is one step of a tick. Phases run in the order of Config.Game.Phases
+// while the game lock is held.
+type Phase interface {
+	Name() string
+	Run(g *Game)
+}
+
+// Names of the built-in phases
+const (
+	PhaseBots       = "bots"
+	PhaseTrade      = "trade"
+	PhaseMove       = "move"
+	PhaseResources  = "resources"
+	PhaseCombat     = "combat"
+	PhaseSpread     = "spread"
+	PhaseConsume    = "consume"
+	PhaseLimitCards = "limit_cards"
+)
+
+// DefaultPhases is the classic order of a tick
+func DefaultPhases() []string {
+	return []string{PhaseBots, PhaseTrade, PhaseMove, PhaseResources, PhaseCombat, PhaseSpread, PhaseConsume, PhaseLimitCards}
+}
+
+// simplePhase adapts a function to the Phase interface
+type simplePhase struct {
+	name    string
+	message string // printed when the phase starts
+	run     func(g *Game)
+}
+
+func (p simplePhase) Name() string {
+	return p.name
+}
+
+func (p simplePhase) Run(g *Game) {
+	g.logln(p.message)
+	p.run(g)
+}
+
+var (
+	phasesMu sync.RWMutex
+	phases   = map[string]Phase{}
+)
+
+func init() {
+	for _, phase := range []simplePhase{
+		{PhaseBots, "Bots giving orders...", func(g *Game) { g.pMap.botsGiveOrders() }},
+		{PhaseTrade, "Trading cards...", func(g *Game) { g.runTrades() }},
+		{PhaseMove, "Moving players...", func(g *Game) { g.pMap.move() }},
+		{PhaseResources, "Distributing ressources...", func(g *Game) { g.gMap.resources() }},
+		{PhaseCombat, "Combat is upon us...", func(g *Game) { g.gMap.handleCombat() }},
+		{PhaseSpread, "The infection is spreading...", func(g *Game) { g.gMap.spread() }},
+		{PhaseConsume, "Players feeding themselves...", func(g *Game) { g.pMap.playersConsume() }},
+		{PhaseLimitCards, "Limiting player inventory", func(g *Game) { g.pMap.limitCards() }},
+	} {
+		RegisterPhase(phase)
+	}
+}
+
+// RegisterPhase makes a phase available to Config.Game.Phases under its name.
+// Phases must be registered before configs naming them are validated.
+func RegisterPhase(phase Phase) {
+	phasesMu.Lock()
+	defer phasesMu.Unlock()
+	if _, exists := phases[phase.Name()]; exists {
+		panic(fmt.Sprintf("phase %q registered twice", phase.Name()))
+	}
+	phases[phase.Name()] = phase
+}
+
+func lookupPhase(name string) (Phase, bool) {
+	phasesMu.RLock()
+	defer phasesMu.RUnlock()
+	phase, exists := phases[name]
+	return phase, exists
+}
+
+// PhaseNames returns the names of all registered phases in sorted order
+func PhaseNames() []string {
+	phasesMu.RLock()
+	defer phasesMu.RUnlock()
+	names := make([]string, 0, len(phases))
+	for name := range phases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PhaseHook is called around every phase of a tick while the game lock is held.
+// elapsed is zero for before hooks.
+type PhaseHook func(g *Game, phase Phase, elapsed time.Duration)
+
+// OnBeforePhase adds a hook that runs before each phase. Hooks are added
+// before the game starts running.
+func (g *Game) OnBeforePhase(hook PhaseHook) {
+	g.beforePhase = append(g.beforePhase, hook)
+}
+
+// OnAfterPhase adds a hook that runs after each phase with the time the phase took
+func (g *Game) OnAfterPhase(hook PhaseHook) {
+	g.afterPhase = append(g.afterPhase, hook)
+}
+
+// runPhase runs one phase with its hooks and logs an EventPhaseCompleted
+func (g *Game) runPhase(phase Phase) {
+	for _, hook := range g.beforePhase {
+		hook(g, phase, 0)
+	}
+	start := time.Now()
+	phase.Run(g)
+	elapsed := time.Since(start)
+
+	g.events.LogEvent(EventPhaseCompleted, "", map[string]interface{}{
+		"phase":       phase.Name(),
+		"duration_us": elapsed.Microseconds(),
+	})
+	for _, hook := range g.afterPhase {
+		hook(g, phase, elapsed)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPhase is a plugin phase that counts how often it ran
+type countingPhase struct {
+	runs *int
+}
+
+func (countingPhase) Name() string {
+	return "test_counting"
+}
+
+func (p countingPhase) Run(g *Game) {
+	*p.runs++
+}
+
+var countingPhaseRuns int
+
+func TestPhases(t *testing.T) {
+	recordPhases := func(g *Game) *[]string {
+		var names []string
+		g.OnBeforePhase(func(g *Game, phase Phase, elapsed time.Duration) {
+			names = append(names, phase.Name())
+		})
+		return &names
+	}
+
+	t.Run("tick runs the default phases in order", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		names := recordPhases(ts.game)
+
+		ts.game.tick()
+
+		assert.Equal(t, DefaultPhases(), *names)
+	})
+
+	t.Run("phase order comes from config", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Game.Phases = []string{PhaseMove, PhaseConsume, PhaseCombat}
+		names := recordPhases(ts.game)
+
+		ts.game.tick()
+
+		assert.Equal(t, []string{PhaseMove, PhaseConsume, PhaseCombat}, *names)
+	})
+
+	t.Run("phases missing from the config do not run", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Game.Phases = []string{PhaseConsume, PhaseCombat}
+		player := ts.getPlayer(ts.createPlayerAt(1, 1))
+		player.setHand([]Card{Food})
+		ts.setupTile(1, 1, Farm, 0)
+
+		ts.game.tick()
+
+		assert.True(t, player.Alive)
+		assert.Equal(t, 0, ts.countCards(player.Cards, Food), "Food should be eaten and the farm give nothing")
+	})
+
+	t.Run("after hooks and events report each phase", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		var timed []string
+		ts.game.OnAfterPhase(func(g *Game, phase Phase, elapsed time.Duration) {
+			assert.GreaterOrEqual(t, elapsed, time.Duration(0))
+			timed = append(timed, phase.Name())
+		})
+
+		ts.game.tick()
+
+		assert.Equal(t, DefaultPhases(), timed)
+		events := ts.game.events.GetPlayerEvents("", EventFilters{EventType: EventPhaseCompleted})
+		require.Len(t, events, len(DefaultPhases()))
+		assert.Equal(t, PhaseBots, events[0].Details["phase"])
+		assert.Contains(t, events[0].Details, "duration_us")
+	})
+
+	t.Run("registered plugin phases can be configured", func(t *testing.T) {
+		if _, exists := lookupPhase(countingPhase{}.Name()); !exists {
+			RegisterPhase(countingPhase{&countingPhaseRuns})
+		}
+		config := gameConfig.clone()
+		config.Game.Phases = append(DefaultPhases(), countingPhase{}.Name())
+		require.NoError(t, config.Validate())
+		ts := setupTestSuite(t)
+		ts.game.config.Game.Phases = config.Game.Phases
+		before := countingPhaseRuns
+
+		ts.game.tick()
+
+		assert.Equal(t, before+1, countingPhaseRuns)
+	})
+
+	t.Run("configured phases must include the built-in ones", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Game.Phases = []string{PhaseMove, PhaseResources, PhaseCombat, PhaseSpread, PhaseConsume, PhaseLimitCards}
+
+		err := config.Validate()
+
+		assert.ErrorContains(t, err, `Game.Phases: is missing the built-in phase "bots"`)
+		assert.ErrorContains(t, err, `Game.Phases: is missing the built-in phase "trade"`)
+		reordered := gameConfig.clone()
+		reordered.Game.Phases = []string{PhaseTrade, PhaseBots, PhaseMove, PhaseResources, PhaseSpread, PhaseCombat, PhaseConsume, PhaseLimitCards}
+		assert.NoError(t, reordered.Validate())
+	})
+
+	t.Run("unknown phases are a config error", func(t *testing.T) {
+		config := gameConfig.clone()
+		config.Game.Phases = []string{PhaseMove, "weather"}
+
+		assert.ErrorContains(t, config.Validate(), "Game.Phases[1]")
+	})
+
+	t.Run("empty phase list falls back to the defaults", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Game.Phases = nil
+		names := recordPhases(ts.game)
+
+		ts.game.tick()
+
+		assert.Equal(t, DefaultPhases(), *names)
+	})
+}
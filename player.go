@@ -13,10 +13,15 @@ type Player struct {
 	Play                   Card
 	Consume                Card
 	Discard                Card
-	Cards                  [5]Card
-	ResearchAcquisitionPos [5][2]int // Track x,y coordinates where each research card was acquired
+	Cards                  []Card   // the hand, see handCapacity and Config.Player.HandLimit
+	ResearchAcquisitionPos [][2]int // Track x,y coordinates where each research card was acquired, parallel to Cards
 	Alive                  bool
+	Ready                  bool // the player's orders for this turn are final
 	IsBot                  bool
+	Strategy               string             `json:",omitempty"` // Name of the bot strategy, empty for humans
+	memory                 map[[2]int]Terrain // terrain of every tile the player has seen, by position
+	cameFrom               *Tile              // tile left in this tick's move phase, nil if the player did not move
+	game                   *Game
 }
 
 func (p *Player) consume() {
@@ -26,7 +31,7 @@ func (p *Player) consume() {
 
 	HandleEmptyConsume(p)
 
-	cardPos, hasCard := hasCardWhere(p.Cards[:], p.Consume)
+	cardPos, hasCard := hasCardWhere(p.Cards, p.Consume)
 	if hasCard {
 		ExecuteConsumption(p, cardPos)
 	} else {
@@ -36,7 +41,7 @@ func (p *Player) consume() {
 
 func HandleEmptyConsume(p *Player) {
 	if p.Consume == None {
-		if _, hasFood := hasCardWhere(p.Cards[:], Food); hasFood {
+		if _, hasFood := hasCardWhere(p.Cards, Food); hasFood {
 			p.Consume = Food
 		} else {
 			p.Consume = Wood
@@ -49,25 +54,24 @@ func ExecuteConsumption(p *Player, cardPos int) {
 	playerX, playerY := p.CurrentTile.XPos, p.CurrentTile.YPos
 
 	if p.Consume == Wood {
-		gMap.fireAttractingTo(playerX, playerY)
+		p.game.gMap.fireAttractingTo(playerX, playerY)
 	}
 
-	eventLogger.LogEvent(EventCardConsumed, p.ID, map[string]interface{}{
+	p.game.events.LogEvent(EventCardConsumed, p.ID, map[string]interface{}{
 		"card":      p.Consume.String(),
 		"x":         playerX,
 		"y":         playerY,
 		"card_slot": cardPos,
 	})
 
-	p.Cards[cardPos] = None
-	p.ResearchAcquisitionPos[cardPos] = [2]int{-1, -1} // Clear research position
+	p.removeCard(cardPos)
 }
 
 // HandleFailedConsumption handles the logic when a player cannot consume the required card.
 func HandleFailedConsumption(p *Player) {
 	playerX, playerY := p.CurrentTile.XPos, p.CurrentTile.YPos
 
-	eventLogger.LogEvent(EventPlayerDeath, p.ID, map[string]interface{}{
+	p.game.events.LogEvent(EventPlayerDeath, p.ID, map[string]interface{}{
 		"reason": "starvation",
 		"card":   p.Consume.String(),
 		"x":      playerX,
@@ -81,21 +85,21 @@ func (p *Player) cardInput(inputCard string) {
 	// Convert input to lowercase for case-insensitive comparison
 	lowerInput := strings.ToLower(inputCard)
 
-	// Check if the input matches a weapon card
-	if lowerInput == "weapon" {
-		// Log weapon play
-		if cardPos, hasWeapon := hasCardWhere(p.Cards[:], Weapon); hasWeapon {
-			eventLogger.LogEvent(EventCardPlayed, p.ID, map[string]interface{}{
-				"card":      Weapon.String(),
+	// Check if the input matches a card played in combat
+	if card, exists := cards[lowerInput]; exists && card.isPlayable() {
+		// Log card play
+		if cardPos, hasCard := hasCardWhere(p.Cards, card); hasCard {
+			p.game.events.LogEvent(EventCardPlayed, p.ID, map[string]interface{}{
+				"card":      card.String(),
 				"card_slot": cardPos,
 				"x":         p.CurrentTile.XPos,
 				"y":         p.CurrentTile.YPos,
 			})
 		}
-		p.Play = Weapon
+		p.Play = card
 	} else if card, exists := cards[lowerInput]; exists {
 		// For other card types, set Consume
-		eventLogger.LogEvent(EventCardSelected, p.ID, map[string]interface{}{
+		p.game.events.LogEvent(EventCardSelected, p.ID, map[string]interface{}{
 			"card":   card.String(),
 			"action": "consume",
 			"x":      p.CurrentTile.XPos,
@@ -105,6 +109,88 @@ func (p *Player) cardInput(inputCard string) {
 	}
 }
 
+// selectDiscard chooses the card the player gives up if its hand is over the
+// limit at the end of the tick. None leaves the choice to limitCards.
+func (p *Player) selectDiscard(card Card) {
+	p.game.events.LogEvent(EventCardSelected, p.ID, map[string]interface{}{
+		"card":   card.String(),
+		"action": "discard",
+		"x":      p.CurrentTile.XPos,
+		"y":      p.CurrentTile.YPos,
+	})
+	p.Discard = card
+}
+
+// handCapacity is the number of cards a hand holds. During a tick a hand may
+// exceed Player.HandLimit by one card, limitCards discards it at the end.
+func (p Player) handCapacity() int {
+	return p.game.config.Player.HandLimit + 1
+}
+
+// addCard puts the card acquired at x, y at the end of the hand. Only research
+// cards remember where they were acquired. It returns false if the hand is full.
+func (p *Player) addCard(card Card, x, y int) bool {
+	if card == None || len(p.Cards) >= p.handCapacity() {
+		return false
+	}
+	pos := [2]int{-1, -1}
+	if card == Research {
+		pos = [2]int{x, y}
+	}
+	p.putCard(card, pos)
+	return true
+}
+
+// removeCard takes the card in the given slot out of the hand, the cards after
+// it move up one slot. The hand is copied, so hands logged in events stay as they were.
+func (p *Player) removeCard(slot int) Card {
+	card := p.Cards[slot]
+	p.Cards = append(p.Cards[:slot:slot], p.Cards[slot+1:]...)
+	p.ResearchAcquisitionPos = append(p.ResearchAcquisitionPos[:slot:slot], p.ResearchAcquisitionPos[slot+1:]...)
+	return card
+}
+
+// setHand replaces the hand, dropping None cards. No card remembers where it was acquired.
+func (p *Player) setHand(cards []Card) {
+	p.Cards = []Card{}
+	p.ResearchAcquisitionPos = [][2]int{}
+	for _, card := range cards {
+		if card != None {
+			p.Cards = append(p.Cards, card)
+			p.ResearchAcquisitionPos = append(p.ResearchAcquisitionPos, [2]int{-1, -1})
+		}
+	}
+}
+
+// discard removes the card in the given slot at the end of a tick. reason is
+// "chosen" for the player's Discard order and "forced" otherwise.
+func (p *Player) discard(slot int, reason string) {
+	card := p.removeCard(slot)
+	p.game.events.LogEvent(EventCardDiscarded, p.ID, map[string]interface{}{
+		"card":      card.String(),
+		"card_slot": slot,
+		"reason":    reason,
+		"x":         p.CurrentTile.XPos,
+		"y":         p.CurrentTile.YPos,
+	})
+}
+
+// drawCard adds the card to the player's hand and logs where it came from.
+// It returns false if the hand is full.
+func (p *Player) drawCard(card Card, source string) bool {
+	if !p.addCard(card, p.CurrentTile.XPos, p.CurrentTile.YPos) {
+		return false
+	}
+	p.game.events.LogEvent(EventCardDrawn, p.ID, map[string]interface{}{
+		"card":      card.String(),
+		"card_slot": len(p.Cards) - 1,
+		"source":    source,
+		"x":         p.CurrentTile.XPos,
+		"y":         p.CurrentTile.YPos,
+	})
+	return true
+}
+
 func (p Player) hasWinCondition() bool {
 	// Must be at a laboratory to win
 	if p.CurrentTile.Terrain != Laboratory {
@@ -130,11 +216,11 @@ func (p Player) hasWinCondition() bool {
 		}
 	}
 
-	if numberOfResearchs < gameConfig.Game.VictoryNumber {
+	if numberOfResearchs < p.game.config.Game.VictoryNumber {
 		return false
 	} else {
-		fmt.Println("Player has won")
-		fmt.Println(p.String())
+		p.game.logln("Player has won")
+		p.game.logln(p.String())
 		return true
 	}
 }
@@ -157,16 +243,32 @@ func (p Player) firstIndexOfCardType(target Card) int {
 	return -1
 }
 
-func (p Player) getHandSize() int { //TODO: Move to method
+func (p Player) countCards(target Card) int {
 	var count = 0
 	for _, card := range p.Cards {
-		if card != None {
+		if card == target {
 			count++
 		}
 	}
 	return count
 }
 
+// usableResearchAt counts the research cards that would count towards a win at
+// the laboratory at x, y, i.e. those not acquired there
+func (p Player) usableResearchAt(x, y int) int {
+	var count = 0
+	for i, card := range p.Cards {
+		if card == Research && p.ResearchAcquisitionPos[i] != [2]int{x, y} {
+			count++
+		}
+	}
+	return count
+}
+
+func (p Player) getHandSize() int {
+	return len(p.Cards)
+}
+
 func (p Player) String() string {
 	var r strings.Builder
 	r.WriteString(p.ID)
@@ -176,10 +278,9 @@ func (p Player) String() string {
 	r.WriteString("|")
 	//r.WriteString(fmt.Sprintf("%d", p.Y))
 	r.WriteString(" ")
-	r.WriteString(p.Cards[0].String())
-	r.WriteString(p.Cards[1].String())
-	r.WriteString(p.Cards[2].String())
-	r.WriteString(p.Cards[3].String())
+	for _, card := range p.Cards {
+		r.WriteString(card.String())
+	}
 	return r.String()
 }
 
@@ -1,34 +1,41 @@
 package main
 
-import "github.com/google/uuid"
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
 
 // TODO: Not sure if relying on coordinates in the Player is a good idea
 type playerMap struct {
+	game    *Game
 	Players map[string]*Player
 }
 
 // TODO: Somehow remove inactive players
 func (pm playerMap) addPlayer(playerName string, entryTile *Tile) string {
-	playerID, _ := uuid.NewV7()
+	playerID, _ := uuid.NewRandomFromReader(pm.game.rng) // Drawn from the game RNG so seeded runs reproduce IDs
 	idString := playerID.String()
 	var player = Player{
 		ID:                     idString,
 		Name:                   playerName,
 		CurrentTile:            entryTile,
-		Direction:              gameConfig.Game.DefaultDirection,
+		Direction:              pm.game.config.Game.DefaultDirection,
 		Play:                   None,
 		Consume:                None,
 		Discard:                None,
-		Cards:                  [5]Card{Food, Wood, Wood, None, None},
-		ResearchAcquisitionPos: [5][2]int{{-1, -1}, {-1, -1}, {-1, -1}, {-1, -1}, {-1, -1}}, // Initialize with invalid positions
+		Cards:                  []Card{Food, Wood, Wood},
+		ResearchAcquisitionPos: [][2]int{{-1, -1}, {-1, -1}, {-1, -1}}, // Initialize with invalid positions
 		Alive:                  true,
 		IsBot:                  false,
+		game:                   pm.game,
 	}
 	pm.Players[idString] = &player
 	entryTile.addPlayer(&player) // Actually add the player to the tile
+	pm.game.rememberVisibleTiles(&player)
 
 	// Log player join event
-	eventLogger.LogEvent(EventPlayerJoin, idString, map[string]interface{}{
+	pm.game.events.LogEvent(EventPlayerJoin, idString, map[string]interface{}{
 		"name": playerName,
 		"x":    entryTile.XPos,
 		"y":    entryTile.YPos,
@@ -37,8 +44,22 @@ func (pm playerMap) addPlayer(playerName string, entryTile *Tile) string {
 	return idString
 }
 
-func (pm playerMap) move() {
+// sortedPlayers returns all players ordered by ID, so that every pass over the
+// players happens in the same order for the same seed.
+func (pm playerMap) sortedPlayers() []*Player {
+	players := make([]*Player, 0, len(pm.Players))
 	for _, player := range pm.Players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].ID < players[j].ID
+	})
+	return players
+}
+
+func (pm playerMap) move() {
+	for _, player := range pm.sortedPlayers() {
+		player.cameFrom = nil
 		if !player.Alive {
 			continue
 		}
@@ -47,7 +68,7 @@ func (pm playerMap) move() {
 		oldX, oldY := oldTile.XPos, oldTile.YPos
 
 		if player.Direction == Stay {
-			eventLogger.LogEvent(EventPlayerMove, player.ID, map[string]interface{}{
+			pm.game.events.LogEvent(EventPlayerMove, player.ID, map[string]interface{}{
 				"from_x": oldX,
 				"from_y": oldY,
 				"to_x":   oldX,
@@ -58,10 +79,10 @@ func (pm playerMap) move() {
 		}
 
 		targetX, targetY := calculateNewPosition(oldX, oldY, player.Direction)
-		clampedX, clampedY := clampToMapBoundaries(targetX, targetY, gMap.width, gMap.height)
+		clampedX, clampedY := clampToMapBoundaries(targetX, targetY, pm.game.gMap.width, pm.game.gMap.height)
 
 		if clampedX == oldX && clampedY == oldY {
-			eventLogger.LogEvent(EventPlayerMove, player.ID, map[string]interface{}{
+			pm.game.events.LogEvent(EventPlayerMove, player.ID, map[string]interface{}{
 				"from_x": oldX,
 				"from_y": oldY,
 				"to_x":   clampedX,
@@ -71,7 +92,7 @@ func (pm playerMap) move() {
 			continue
 		}
 
-		handlePlayerMovement(player, oldTile, clampedX, clampedY)
+		pm.handlePlayerMovement(player, oldTile, clampedX, clampedY)
 	}
 }
 
@@ -108,48 +129,73 @@ func clampToMapBoundaries(x, y, width, height int) (int, int) {
 }
 
 // handlePlayerMovement updates the player's position and logs the event.
-func handlePlayerMovement(player *Player, oldTile *Tile, newX, newY int) {
-	newTile := gMap.getTileFromPos(newX, newY)
+func (pm playerMap) handlePlayerMovement(player *Player, oldTile *Tile, newX, newY int) {
+	newTile := pm.game.gMap.getTileFromPos(newX, newY)
 	oldTile.removePlayer(player)
 	newTile.addPlayer(player)
 	player.CurrentTile = newTile
+	player.cameFrom = oldTile
 
-	eventLogger.LogEvent(EventPlayerMove, player.ID, map[string]interface{}{
+	pm.game.events.LogEvent(EventPlayerMove, player.ID, map[string]interface{}{
 		"from_x": oldTile.XPos,
 		"from_y": oldTile.YPos,
 		"to_x":   newX,
 		"to_y":   newY,
 	})
 
-	player.Direction = gameConfig.Game.DefaultDirection
+	player.Direction = pm.game.config.Game.DefaultDirection
+}
+
+// teleport puts the player on the tile at x, y outside of the regular movement
+func (pm playerMap) teleport(player *Player, x, y int) {
+	oldTile := player.CurrentTile
+	newTile := pm.game.gMap.getTileFromPos(x, y)
+	oldTile.removePlayer(player)
+	newTile.addPlayer(player)
+	player.CurrentTile = newTile
+
+	pm.game.events.LogEvent(EventPlayerMove, player.ID, map[string]interface{}{
+		"from_x": oldTile.XPos,
+		"from_y": oldTile.YPos,
+		"to_x":   x,
+		"to_y":   y,
+		"reason": "teleported",
+	})
+	pm.game.rememberVisibleTiles(player)
 }
 
 func (p playerMap) playersConsume() {
-	for _, playerPtr := range p.Players {
+	for _, playerPtr := range p.sortedPlayers() {
 		playerPtr.consume()
 	}
 }
 
+// limitCards brings every hand over Player.HandLimit back to the limit. The card
+// the player chose to discard goes first, then the most recently acquired cards.
 func (pm playerMap) limitCards() {
-	for mapKey := range pm.Players {
-		var player = pm.Players[mapKey]
-		if player.getHandSize() > 4 { //TODO: Make configurable
-			var cardPos, hasCard = hasCardWhere(player.Cards[:], player.Discard)
-			if hasCard && player.Discard != None && cardPos > -1 { //Better safe...
-				player.Cards[cardPos] = None
-				player.ResearchAcquisitionPos[cardPos] = [2]int{-1, -1} // Clear research position
-			} else {
-				player.Cards[4] = None
-				player.ResearchAcquisitionPos[4] = [2]int{-1, -1} // Clear research position
+	limit := pm.game.config.Player.HandLimit
+	for _, player := range pm.sortedPlayers() {
+		if player.getHandSize() > limit && player.Discard != None {
+			if cardPos, hasCard := hasCardWhere(player.Cards, player.Discard); hasCard {
+				player.discard(cardPos, "chosen")
 			}
 		}
+		for player.getHandSize() > limit {
+			player.discard(player.getHandSize()-1, "forced")
+		}
 		player.Discard = None
-		pm.Players[mapKey] = player
 	}
 }
 
-func (pm playerMap) havePlayersWon() bool {
+// rememberVisibleTiles updates what every player remembers of the map
+func (pm playerMap) rememberVisibleTiles() {
 	for _, player := range pm.Players {
+		pm.game.rememberVisibleTiles(player)
+	}
+}
+
+func (pm playerMap) havePlayersWon() bool {
+	for _, player := range pm.sortedPlayers() {
 		if player.hasWinCondition() {
 			return true
 		}
@@ -157,6 +203,40 @@ func (pm playerMap) havePlayersWon() bool {
 	return false
 }
 
+// playersNotReady counts the living human players that have not marked themselves ready
+func (pm playerMap) playersNotReady() (waiting int, humans int) {
+	for _, player := range pm.Players {
+		if player.Alive && !player.IsBot {
+			humans++
+			if !player.Ready {
+				waiting++
+			}
+		}
+	}
+	return waiting, humans
+}
+
+// allHumansReady is true if there are living human players and all of them are ready
+func (pm playerMap) allHumansReady() bool {
+	waiting, humans := pm.playersNotReady()
+	return humans > 0 && waiting == 0
+}
+
+func (pm playerMap) resetReady() {
+	for _, player := range pm.Players {
+		player.Ready = false
+	}
+}
+
+func (pm playerMap) anyAlive() bool {
+	for _, player := range pm.Players {
+		if player.Alive {
+			return true
+		}
+	}
+	return false
+}
+
 func (pm playerMap) getPlayer(id string) Player {
 	return *pm.Players[id]
 }
@@ -165,8 +245,34 @@ func (pm playerMap) getPlayerPtr(id string) *Player {
 	return pm.Players[id]
 }
 
-func NewPlayerMap() playerMap {
-	return playerMap{make(map[string]*Player)}
+func NewPlayerMap(game *Game) playerMap {
+	return playerMap{game, make(map[string]*Player)}
+}
+
+// addBot adds a player that is controlled by the server using the named strategy
+func (pm playerMap) addBot(botName string, strategy string, entryTile *Tile) string {
+	id := pm.addPlayer(botName, entryTile)
+	pm.Players[id].IsBot = true
+	pm.Players[id].Strategy = strategy
+	return id
 }
 
-//TODO: Move bot stuff here?
+// botsGiveOrders lets every living bot choose its orders for the coming tick
+func (pm playerMap) botsGiveOrders() {
+	for _, player := range pm.sortedPlayers() {
+		if !player.IsBot || !player.Alive {
+			continue
+		}
+		strategy, exists := botStrategies[player.Strategy]
+		if !exists {
+			strategy = botStrategies[BotStrategyBaseline]
+		}
+		view := BotView{
+			Player:       *player,
+			Surroundings: pm.game.playerSurroundings(*player),
+			Events:       pm.game.events.GetPlayerEvents(player.ID, EventFilters{LastTurns: pm.game.config.Api.DefaultReportedTurns}),
+			Config:       pm.game.config,
+		}
+		strategy.Decide(view, pm.game.rng).apply(player)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// previewMaxOutcomes caps the roll sequences enumerated for exact odds. Fights
+// with more outcomes are sampled previewSamples times instead.
+const (
+	previewMaxOutcomes = 10000
+	previewSamples     = 5000
+	previewSeed        = 1
+)
+
+// PlayOdds are the chances of a fight if the player plays the given card
+type PlayOdds struct {
+	Play          Card
+	WinChance     float64 // the players clear the tile
+	SurviveChance float64 // the player is alive after the fight
+}
+
+// CombatPreview describes the fight a player would join by moving in a
+// direction. Other players are assumed to roll, as their cards are private.
+type CombatPreview struct {
+	X        int
+	Y        int
+	Zombies  int
+	Players  []string // the fighters in the order they fight
+	Resolver string
+	Exact    bool // false if the odds were sampled
+	Options  []PlayOdds
+}
+
+// destination returns the tile a player ends the move phase on when moving in
+// the given direction. Dead players do not move.
+func (g *Game) destination(p *Player, direction Direction) *Tile {
+	if !p.Alive {
+		return p.CurrentTile
+	}
+	x, y := calculateNewPosition(p.CurrentTile.XPos, p.CurrentTile.YPos, direction)
+	x, y = clampToMapBoundaries(x, y, g.gMap.width, g.gMap.height)
+	return g.gMap.getTileFromPos(x, y)
+}
+
+// plannedFight is a fight as seen by a player before the move phase, copied out
+// of the game so its odds can be computed without holding g.mu
+type plannedFight struct {
+	preview  CombatPreview
+	resolver CombatResolver
+	input    CombatInput
+	self     int    // the index of the player among the fighters
+	plays    []Card // the cards the player can play
+}
+
+// combatPreview computes the odds of the fight on the tile the player reaches by
+// moving in direction. The caller holds g.mu.
+func (g *Game) combatPreview(player *Player, direction Direction) CombatPreview {
+	return g.planFight(player, direction).odds()
+}
+
+// planFight collects the fight on the tile the player reaches by moving in
+// direction, with the fighters in the order the move phase leaves them on that
+// tile. The caller holds g.mu.
+func (g *Game) planFight(player *Player, direction Direction) plannedFight {
+	plannedDirection := func(p *Player) Direction {
+		if p == player {
+			return direction
+		}
+		return p.Direction
+	}
+
+	target := g.destination(player, direction)
+	var fighters []*Player
+	for _, p := range target.playerPtrs {
+		if g.destination(p, plannedDirection(p)) == target {
+			fighters = append(fighters, p)
+		}
+	}
+	for _, p := range g.pMap.sortedPlayers() {
+		if p.CurrentTile != target && g.destination(p, plannedDirection(p)) == target {
+			fighters = append(fighters, p)
+		}
+	}
+
+	fight := plannedFight{
+		preview: CombatPreview{
+			X:        target.XPos,
+			Y:        target.YPos,
+			Zombies:  target.Zombies,
+			Players:  []string{},
+			Resolver: g.config.Combat.Resolver,
+			Exact:    true,
+		},
+		resolver: g.combatResolver(),
+		input:    CombatInput{Zombies: target.Zombies, Config: g.config.clone()},
+		plays:    []Card{None},
+	}
+	for i, p := range fighters {
+		if p == player {
+			fight.self = i
+		}
+		fight.preview.Players = append(fight.preview.Players, p.ID)
+		fight.input.Fighters = append(fight.input.Fighters, Fighter{ID: p.ID, Play: None, CanRetreat: p.Alive && p.CurrentTile != target})
+	}
+	for _, play := range []Card{Weapon, Dice} {
+		if player.countCards(play) > 0 {
+			fight.plays = append(fight.plays, play)
+		}
+	}
+	return fight
+}
+
+// odds computes the odds of every card the player can play
+func (f plannedFight) odds() CombatPreview {
+	preview := f.preview
+	for _, play := range f.plays {
+		f.input.Fighters[f.self].Play = play
+		odds, exact := combatOdds(f.resolver, f.input, f.self)
+		odds.Play = play
+		preview.Options = append(preview.Options, odds)
+		preview.Exact = preview.Exact && exact
+	}
+	return preview
+}
+
+// combatOdds runs the resolver on every possible sequence of rolls, or on a
+// sample if there are too many, and sums up the chances of the fighter self
+func combatOdds(resolver CombatResolver, input CombatInput, self int) (PlayOdds, bool) {
+	var odds PlayOdds
+	add := func(outcome CombatOutcome, weight float64) {
+		if outcome.Won {
+			odds.WinChance += weight
+		}
+		if !outcome.Killed[self] {
+			odds.SurviveChance += weight
+		}
+	}
+
+	roller := &enumeratingRoller{config: input.Config}
+	for outcomes := 0; outcomes < previewMaxOutcomes; outcomes++ {
+		roller.depth = 0
+		outcome := resolver.Resolve(input, roller)
+		add(outcome, roller.weight())
+		if !roller.advance() {
+			return odds, true
+		}
+	}
+
+	odds = PlayOdds{}
+	sampler := &samplingRoller{config: input.Config, rng: NewRNG(previewSeed)}
+	for i := 0; i < previewSamples; i++ {
+		add(resolver.Resolve(input, sampler), 1/float64(previewSamples))
+	}
+	return odds, false
+}
+
+// rollBranch is a random choice made in a fight: choice out of n equally likely values
+type rollBranch struct {
+	choice int
+	n      int
+}
+
+// enumeratingRoller replays the choices of path and then picks the first value
+// of every new choice. advance moves on to the next sequence like an odometer.
+type enumeratingRoller struct {
+	config *Config
+	path   []rollBranch
+	depth  int
+}
+
+func (r *enumeratingRoller) Roll(fighter int) int {
+	minAttack, maxAttack := r.config.Combat.PlayerMinAttack, r.config.Combat.PlayerMaxAttack
+	return r.Intn(maxAttack-minAttack+1) + minAttack
+}
+
+func (r *enumeratingRoller) Intn(n int) int {
+	if r.depth == len(r.path) {
+		r.path = append(r.path, rollBranch{choice: 0, n: n})
+	}
+	r.depth++
+	return r.path[r.depth-1].choice
+}
+
+// weight is the probability of the sequence just replayed
+func (r *enumeratingRoller) weight() float64 {
+	weight := 1.0
+	for _, branch := range r.path[:r.depth] {
+		weight /= float64(branch.n)
+	}
+	return weight
+}
+
+// advance moves to the next sequence and returns false once all were replayed
+func (r *enumeratingRoller) advance() bool {
+	r.path = r.path[:r.depth]
+	for len(r.path) > 0 {
+		last := &r.path[len(r.path)-1]
+		last.choice++
+		if last.choice < last.n {
+			return true
+		}
+		r.path = r.path[:len(r.path)-1]
+	}
+	return false
+}
+
+// samplingRoller rolls like a real fight, without logging
+type samplingRoller struct {
+	config *Config
+	rng    *RNG
+}
+
+func (r *samplingRoller) Roll(fighter int) int {
+	minAttack, maxAttack := r.config.Combat.PlayerMinAttack, r.config.Combat.PlayerMaxAttack
+	return r.rng.Intn(maxAttack-minAttack+1) + minAttack
+}
+
+func (r *samplingRoller) Intn(n int) int {
+	return r.rng.Intn(n)
+}
+
+// getCombatPreviewHandler returns the odds of the fight the player would join
+// by moving in the direction given by the direction query parameter
+func getCombatPreviewHandler(c *gin.Context) {
+	direction, exists := directions[strings.ToLower(c.Query("direction"))]
+	if !exists {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_direction", "Unknown direction: "+c.Query("direction"))
+		return
+	}
+
+	game := currentGame(c)
+	game.mu.RLock()
+	player := game.getPlayerOrNil(c.Param("id"))
+	if !player.Alive {
+		game.mu.RUnlock()
+		sendErrorResponse(c, http.StatusBadRequest, "player_dead", "Dead players do not fight")
+		return
+	}
+	fight := game.planFight(player, direction)
+	game.mu.RUnlock()
+
+	// The resolver runs thousands of times for large fights, so the odds are
+	// computed on the copied fight without blocking the game loop
+	sendSuccessResponse(c, http.StatusOK, gin.H{"preview": fight.odds()})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombatPreview(t *testing.T) {
+	oddsOf := func(t *testing.T, preview CombatPreview, play Card) PlayOdds {
+		for _, odds := range preview.Options {
+			if odds.Play == play {
+				return odds
+			}
+		}
+		require.Failf(t, "missing option", "no odds for %s", play)
+		return PlayOdds{}
+	}
+
+	t.Run("odds for every card the player holds", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.setupTile(5, 4, Farm, 3)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+		player.setHand([]Card{Weapon, Dice})
+
+		preview := ts.game.combatPreview(player, North)
+
+		assert.Equal(t, 5, preview.X)
+		assert.Equal(t, 4, preview.Y)
+		assert.Equal(t, 3, preview.Zombies)
+		assert.True(t, preview.Exact)
+		require.Len(t, preview.Options, 3)
+		assert.InDelta(t, 0.5, oddsOf(t, preview, None).WinChance, 1e-9, "a roll of 4 to 6 beats 3 zombies")
+		assert.InDelta(t, 0.5, oddsOf(t, preview, None).SurviveChance, 1e-9)
+		assert.InDelta(t, 0.0, oddsOf(t, preview, Weapon).WinChance, 1e-9, "a weapon of strength 3 does not beat 3 zombies")
+		assert.InDelta(t, 0.75, oddsOf(t, preview, Dice).WinChance, 1e-9, "the better of two rolls")
+	})
+
+	t.Run("cards the player lacks are not offered", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+
+		preview := ts.game.combatPreview(player, Stay)
+
+		require.Len(t, preview.Options, 1)
+		assert.Equal(t, None, preview.Options[0].Play)
+	})
+
+	t.Run("players planning to move there fight along", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.setupTile(5, 4, Farm, 7)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+		ally := ts.getPlayer(ts.playerMap.addPlayer("Ally", ts.gameMap.getTileFromPos(5, 3)))
+		ally.Direction = South
+		leaving := ts.getPlayer(ts.playerMap.addPlayer("Leaving", ts.gameMap.getTileFromPos(5, 4)))
+		leaving.Direction = West
+
+		preview := ts.game.combatPreview(player, North)
+
+		assert.ElementsMatch(t, []string{player.ID, ally.ID}, preview.Players)
+		assert.InDelta(t, 15.0/36, oddsOf(t, preview, None).WinChance, 1e-9, "two rolls summing to more than 7")
+	})
+
+	t.Run("odds follow the active resolver", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Combat.Resolver = CombatResolverAttrition
+		ts.setupTile(5, 5, Farm, 3)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+
+		preview := ts.game.combatPreview(player, Stay)
+
+		assert.Equal(t, CombatResolverAttrition, preview.Resolver)
+		assert.InDelta(t, 4.0/6, oddsOf(t, preview, None).WinChance, 1e-9, "a roll of 3 or more kills all 3 zombies")
+		assert.InDelta(t, 4.0/6, oddsOf(t, preview, None).SurviveChance, 1e-9)
+	})
+
+	t.Run("large fights are sampled", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Combat.Resolver = CombatResolverRetreat
+		ts.game.config.Combat.RetreatChance = 50
+		ts.setupTile(5, 5, Farm, 10)
+		player := ts.getPlayer(ts.createPlayerAt(5, 6))
+		ts.getPlayer(ts.playerMap.addPlayer("Bob", ts.gameMap.getTileFromPos(4, 5))).Direction = East
+		ts.getPlayer(ts.playerMap.addPlayer("Carol", ts.gameMap.getTileFromPos(6, 5))).Direction = West
+
+		preview := ts.game.combatPreview(player, North)
+
+		assert.False(t, preview.Exact)
+		assert.InDelta(t, 0.5, oddsOf(t, preview, None).WinChance, 0.02, "three rolls summing to more than 10")
+		assert.InDelta(t, 0.75, oddsOf(t, preview, None).SurviveChance, 0.02)
+	})
+
+	t.Run("preview endpoint", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		player := as.addPlayer("", "alice")
+		path := "/player/" + player.ID + "/combat-preview"
+
+		rec := as.requestWithToken(http.MethodGet, path+"?direction=north", "", player.Token)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var response struct{ Preview CombatPreview }
+		as.decode(rec, &response)
+		assert.Equal(t, []string{player.ID}, response.Preview.Players)
+		assert.NotEmpty(t, response.Preview.Options)
+
+		assert.Equal(t, http.StatusBadRequest,
+			as.requestWithToken(http.MethodGet, path+"?direction=up", "", player.Token).Code)
+		assert.Equal(t, http.StatusUnauthorized, as.request(http.MethodGet, path+"?direction=north", "").Code)
+	})
+}
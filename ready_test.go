@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReady(t *testing.T) {
+	t.Run("turn ends early once every living human is ready", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Game.MinTurnLength = 0
+		alice := ts.getPlayer(ts.createPlayerAt(1, 1))
+		bob := ts.getPlayer(ts.createPlayerAt(2, 2))
+		bot := ts.getPlayer(ts.playerMap.addBot("Bot", BotStrategyBaseline, ts.gameMap.getTileFromPos(3, 3)))
+		dead := ts.getPlayer(ts.createPlayerAt(4, 4))
+		dead.Alive = false
+
+		assert.False(t, ts.game.canEndTurn())
+		alice.Ready = true
+		assert.False(t, ts.game.canEndTurn(), "Bob is not ready yet")
+		bob.Ready = true
+		assert.True(t, ts.game.canEndTurn(), "Bots and dead players are not waited for")
+		assert.False(t, bot.Ready)
+	})
+
+	t.Run("minimum turn length holds back early ticks", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Game.MinTurnLength = 2
+		ts.getPlayer(ts.createPlayerAt(1, 1)).Ready = true
+
+		assert.False(t, ts.game.canEndTurn())
+		ts.game.timerDown()
+		assert.False(t, ts.game.canEndTurn())
+		ts.game.timerDown()
+		assert.True(t, ts.game.canEndTurn())
+	})
+
+	t.Run("games without humans wait for the timer", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Game.MinTurnLength = 0
+		ts.playerMap.addBot("Bot", BotStrategyBaseline, ts.gameMap.getTileFromPos(1, 1))
+
+		assert.False(t, ts.game.canEndTurn())
+	})
+
+	t.Run("ready is reset by the tick", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		player := ts.getPlayer(ts.createPlayerAt(1, 1))
+		player.Ready = true
+
+		ts.game.endTurn()
+
+		assert.False(t, player.Ready)
+	})
+
+	t.Run("loop resolves the tick right after the last player is ready", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		game := as.registry.get(defaultGameID)
+		game.config.Game.TurnLength = 60
+		game.config.Game.MinTurnLength = 0
+		game.state.resetTime()
+		game.quiet = true
+		player := as.addPlayer("", "Alice")
+		notices := game.ticks.subscribe()
+		defer game.ticks.unsubscribe(notices)
+		go game.run()
+		defer func() {
+			game.mu.Lock()
+			game.state.win()
+			game.mu.Unlock()
+			game.wakeLoop()
+		}()
+
+		rec := as.requestWithToken(http.MethodPut, "/player/"+player.ID+"/ready", "", player.Token)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case notice := <-notices:
+				if notice.Type == TickFinished {
+					return
+				}
+			case <-timeout:
+				t.Fatal("Tick was not resolved early")
+			}
+		}
+	})
+
+	t.Run("ready endpoints", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		alice := as.addPlayer("", "Alice")
+		as.addPlayer("", "Bob")
+		path := "/player/" + alice.ID + "/ready"
+
+		assert.Equal(t, http.StatusUnauthorized, as.request(http.MethodPut, path, "").Code)
+
+		rec := as.requestWithToken(http.MethodPut, path, "", alice.Token)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			Ready      bool `json:"ready"`
+			WaitingFor int  `json:"waiting_for"`
+		}
+		as.decode(rec, &response)
+		assert.True(t, response.Ready)
+		assert.Equal(t, 1, response.WaitingFor)
+
+		rec = as.requestWithToken(http.MethodDelete, path, "", alice.Token)
+		require.Equal(t, http.StatusOK, rec.Code)
+		as.decode(rec, &response)
+		assert.False(t, response.Ready)
+		assert.Equal(t, 2, response.WaitingFor)
+	})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MapWindow selects the part of the map to render. A Width or Height of 0
+// extends the window to the edge of the map.
+type MapWindow struct {
+	X, Y          int
+	Width, Height int
+}
+
+// clamp fits the window onto a map of the given size
+func (w MapWindow) clamp(mapWidth, mapHeight int) MapWindow {
+	w.X = min(max(w.X, 0), mapWidth)
+	w.Y = min(max(w.Y, 0), mapHeight)
+	if w.Width <= 0 || w.X+w.Width > mapWidth {
+		w.Width = mapWidth - w.X
+	}
+	if w.Height <= 0 || w.Y+w.Height > mapHeight {
+		w.Height = mapHeight - w.Y
+	}
+	return w
+}
+
+// renderGlyph returns the symbol of a terrain, an ASCII letter or its emoji
+func renderGlyph(t Terrain, ascii bool) string {
+	if !ascii {
+		return t.toChar()
+	}
+	if letter, exists := mapFileLetters[t]; exists {
+		return string(letter)
+	}
+	return "#"
+}
+
+// render draws a window of the map, one row of tiles per line. Every tile shows
+// its terrain followed by its zombie count and, after an @, its player count.
+// Empty counts are left blank, counts that do not fit are shown as 99 and @9.
+//
+//	  0     1     2
+//	0 T 3@1 F     C12
+func (g gameMap) render(out io.Writer, window MapWindow, ascii bool) error {
+	window = window.clamp(g.width, g.height)
+	glyphWidth := 2
+	if ascii {
+		glyphWidth = 1
+	}
+	cellWidth := glyphWidth + 5
+
+	w := bufio.NewWriter(out)
+	fmt.Fprint(w, "    ")
+	for x := window.X; x < window.X+window.Width; x++ {
+		fmt.Fprintf(w, "%-*d", cellWidth, x)
+	}
+	fmt.Fprintln(w)
+
+	for y := window.Y; y < window.Y+window.Height; y++ {
+		fmt.Fprintf(w, "%3d ", y)
+		for x := window.X; x < window.X+window.Width; x++ {
+			tile := g.gMap[x][y]
+			zombies := ""
+			if tile.Zombies > 0 {
+				zombies = strconv.Itoa(min(tile.Zombies, 99))
+			}
+			players := ""
+			if len(tile.playerPtrs) > 0 {
+				players = "@" + strconv.Itoa(min(len(tile.playerPtrs), 9))
+			}
+			fmt.Fprintf(w, "%s%2s%-2s ", renderGlyph(tile.Terrain, ascii), zombies, players)
+		}
+		fmt.Fprintln(w)
+	}
+
+	var legend []string
+	for _, terrain := range terrainTypes[:Edge] {
+		legend = append(legend, renderGlyph(terrain, ascii)+" "+terrain.toString())
+	}
+	fmt.Fprintf(w, "%s, number: zombies, @n: players\n", strings.Join(legend, ", "))
+	return w.Flush()
+}
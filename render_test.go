@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	renderLines := func(t *testing.T, g gameMap, window MapWindow, ascii bool) []string {
+		var out bytes.Buffer
+		require.NoError(t, g.render(&out, window, ascii))
+		return strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	}
+
+	t.Run("tiles show terrain, zombies and players", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.setupTile(0, 0, Forest, 3)
+		ts.setupTile(1, 0, Farm, 0)
+		ts.setupTile(2, 0, City, 150)
+		ts.createPlayerAt(0, 0)
+
+		lines := renderLines(t, *ts.gameMap, MapWindow{Width: 3, Height: 1}, true)
+
+		require.Len(t, lines, 3)
+		assert.Equal(t, "    0     1     2", strings.TrimRight(lines[0], " "))
+		assert.Equal(t, "  0 T 3@1 F     C99", strings.TrimRight(lines[1], " "))
+		assert.Contains(t, lines[2], "L Laboratory")
+	})
+
+	t.Run("emoji glyphs are used by default", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.setupTile(0, 0, Laboratory, 0)
+
+		lines := renderLines(t, *ts.gameMap, MapWindow{Width: 1, Height: 1}, false)
+
+		assert.True(t, strings.HasPrefix(lines[1], "  0 "+Laboratory.toChar()))
+	})
+
+	t.Run("window is clamped to the map", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		width, height := ts.gameMap.width, ts.gameMap.height
+
+		lines := renderLines(t, *ts.gameMap, MapWindow{X: width - 2, Y: height - 3, Width: 10, Height: 10}, true)
+
+		require.Len(t, lines, 3+2)
+		assert.Equal(t, []string{strconv.Itoa(width - 2), strconv.Itoa(width - 1)}, strings.Fields(lines[0]))
+		assert.Len(t, renderLines(t, *ts.gameMap, MapWindow{}, true), height+2)
+	})
+
+	t.Run("admin endpoint renders a window", func(t *testing.T) {
+		as, game := setupAdminTestSuite(t)
+		game.gMap.getTileFromPos(1, 2).Terrain = City
+		game.gMap.getTileFromPos(1, 2).Zombies = 7
+
+		rec := as.requestWithToken(http.MethodGet, "/admin/render?x=1&y=2&width=1&height=1&ascii=true", "", "secret")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "  2 C 7")
+		assert.Equal(t, http.StatusBadRequest,
+			as.requestWithToken(http.MethodGet, "/admin/render?x=left", "", "secret").Code)
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+)
+
+// splitMix64 is a rand.Source64 whose whole state is a single integer,
+// which keeps the generator cheap to copy, record and restore.
+type splitMix64 struct {
+	state uint64
+}
+
+func (s *splitMix64) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *splitMix64) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *splitMix64) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// RNG is the single source of randomness of a game. Map generation, dice rolls,
+// spawn selection and player IDs all draw from it, so the same seed and the same
+// sequence of player orders reproduce a game exactly.
+type RNG struct {
+	rand   *rand.Rand
+	source *splitMix64
+	seed   int64
+}
+
+// NewRNG creates a generator for the given seed. A seed of 0 picks one from the clock.
+func NewRNG(seed int64) *RNG {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	source := &splitMix64{}
+	source.Seed(seed)
+	return &RNG{rand: rand.New(source), source: source, seed: seed}
+}
+
+// Seed returns the seed the generator was created with.
+func (r *RNG) Seed() int64 {
+	return r.seed
+}
+
+// State returns the current position of the generator.
+func (r *RNG) State() uint64 {
+	return r.source.state
+}
+
+// SetState moves the generator to a position previously returned by State.
+func (r *RNG) SetState(state uint64) {
+	r.source.state = state
+}
+
+// Intn returns a number in [0, n).
+func (r *RNG) Intn(n int) int {
+	return r.rand.Intn(n)
+}
+
+// Int63 returns a non-negative 63-bit number.
+func (r *RNG) Int63() int64 {
+	return r.rand.Int63()
+}
+
+// Float64 returns a number in [0.0, 1.0).
+func (r *RNG) Float64() float64 {
+	return r.rand.Float64()
+}
+
+// Derive returns an independent generator seeded from this one, for work that
+// runs concurrently but must still be reproducible.
+func (r *RNG) Derive() *RNG {
+	seed := r.Int63()
+	if seed == 0 {
+		seed = 1
+	}
+	return NewRNG(seed)
+}
+
+// Read fills p with random bytes. Every call consumes whole words from the
+// source so the generator state stays meaningful after partial reads.
+func (r *RNG) Read(p []byte) (int, error) {
+	for i := 0; i < len(p); i += 8 {
+		word := r.source.Uint64()
+		for j := 0; j < 8 && i+j < len(p); j++ {
+			p[i+j] = byte(word >> (8 * j))
+		}
+	}
+	return len(p), nil
+}
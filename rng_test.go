@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runSeededGame plays a few ticks with fixed orders and returns the resulting world.
+func runSeededGame(seed int64) ([]string, []Player) {
+	config := gameConfig.clone()
+	config.Game.Seed = seed
+	game, _ := NewGame("seeded", config)
+
+	ids := []string{
+		game.pMap.addPlayer("Alice", game.gMap.getNewPlayerEntryTile()),
+		game.pMap.addPlayer("Bob", game.gMap.getNewPlayerEntryTile()),
+	}
+	for turn := 0; turn < 5; turn++ {
+		game.pMap.getPlayerPtr(ids[0]).Direction = Directions[turn%len(Directions)]
+		game.pMap.getPlayerPtr(ids[1]).Direction = West
+		game.tick()
+	}
+
+	var tiles []string
+	for x := range game.gMap.gMap {
+		for _, tile := range game.gMap.gMap[x] {
+			tiles = append(tiles, tile.toString())
+		}
+	}
+	var players []Player
+	for _, player := range game.pMap.sortedPlayers() {
+		players = append(players, *player)
+	}
+	return tiles, players
+}
+
+func TestSeededRuns(t *testing.T) {
+	t.Run("same seed and orders reproduce the game", func(t *testing.T) {
+		tilesA, playersA := runSeededGame(42)
+		tilesB, playersB := runSeededGame(42)
+
+		assert.Equal(t, tilesA, tilesB, "Maps should be identical")
+		assert.Equal(t, len(playersA), len(playersB))
+		for i := range playersA {
+			assert.Equal(t, playersA[i].ID, playersB[i].ID, "Player IDs should be identical")
+			assert.Equal(t, playersA[i].Cards, playersB[i].Cards, "Hands should be identical")
+			assert.Equal(t, playersA[i].Alive, playersB[i].Alive, "Survival should be identical")
+			assert.Equal(t, playersA[i].CurrentTile.toString(), playersB[i].CurrentTile.toString())
+		}
+	})
+
+	t.Run("different seeds produce different maps", func(t *testing.T) {
+		tilesA, _ := runSeededGame(1)
+		tilesB, _ := runSeededGame(2)
+
+		assert.NotEqual(t, tilesA, tilesB)
+	})
+
+	t.Run("restored state continues the sequence", func(t *testing.T) {
+		a := NewRNG(7)
+		a.Intn(100)
+		state := a.State()
+		expected := []int{a.Intn(100), a.Intn(100), a.Intn(100)}
+
+		b := NewRNG(7)
+		b.SetState(state)
+
+		assert.Equal(t, expected, []int{b.Intn(100), b.Intn(100), b.Intn(100)})
+	})
+}
+
+func TestRollDice(t *testing.T) {
+	t.Run("rolls stay within configured attack range", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		dice := NewRNG(3)
+		seen := map[int]bool{}
+
+		for i := 0; i < 500; i++ {
+			result := ts.game.rollDice(dice, "TestPlayer1")
+			assert.GreaterOrEqual(t, result, gameConfig.Combat.PlayerMinAttack)
+			assert.LessOrEqual(t, result, gameConfig.Combat.PlayerMaxAttack)
+			seen[result] = true
+		}
+
+		assert.Len(t, seen, gameConfig.Combat.PlayerMaxAttack-gameConfig.Combat.PlayerMinAttack+1,
+			"Every value in range should come up")
+	})
+}
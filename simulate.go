@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Output formats of the simulate command
+const (
+	SimulationFormatCSV  = "csv"
+	SimulationFormatJSON = "json"
+)
+
+// SimulationResult summarizes one simulated game
+type SimulationResult struct {
+	Settings         map[string]string // the swept config values this game ran with
+	Game             int               // index of the game among those with the same settings
+	Seed             int64
+	Turns            int // ticks played until the game ended
+	Won              bool
+	Bots             int
+	Survivors        int
+	StarvationDeaths int
+	CombatDeaths     int
+	ZombieTotals     []int // zombies on the map after each tick
+}
+
+// SweepParameter is a config field, written as a dotted path like
+// "Combat.ZombieCutoff", and the values a simulation tries for it
+type SweepParameter struct {
+	Field  string
+	Values []string
+}
+
+// parseSweep parses "Field=value1,value2,..."
+func parseSweep(s string) (SweepParameter, error) {
+	field, values, found := strings.Cut(s, "=")
+	if !found || field == "" || values == "" {
+		return SweepParameter{}, fmt.Errorf("invalid sweep %q, expected Field=value1,value2", s)
+	}
+	return SweepParameter{Field: field, Values: strings.Split(values, ",")}, nil
+}
+
+// applySetting sets a single config field from its dotted path. The value is
+// merged like a config file, so it accepts the same names for cards, terrains
+// and directions. Values that are not valid JSON are taken as strings.
+func (c *Config) applySetting(field, value string) error {
+	var encoded json.RawMessage
+	if json.Valid([]byte(value)) {
+		encoded = json.RawMessage(value)
+	} else {
+		quoted, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		encoded = quoted
+	}
+
+	path := strings.Split(field, ".")
+	var doc interface{} = encoded
+	for i := len(path) - 1; i >= 0; i-- {
+		doc = map[string]interface{}{path[i]: doc}
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := c.merge(data, ".json"); err != nil {
+		return fmt.Errorf("setting %s to %s: %w", field, value, err)
+	}
+	return nil
+}
+
+// sweepSettings returns every combination of the sweep values
+func sweepSettings(sweep []SweepParameter) []map[string]string {
+	combinations := []map[string]string{{}}
+	for _, parameter := range sweep {
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range parameter.Values {
+				settings := make(map[string]string, len(combination)+1)
+				for field, v := range combination {
+					settings[field] = v
+				}
+				settings[parameter.Field] = value
+				next = append(next, settings)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// simulate runs games games of bots for every combination of the sweep values.
+// Game i of each combination uses seed base.Game.Seed+i, so combinations are
+// compared on the same sequence of seeds. A base seed of 0 picks one from the clock.
+func simulate(base *Config, sweep []SweepParameter, games int) ([]SimulationResult, error) {
+	baseSeed := base.Game.Seed
+	if baseSeed == 0 {
+		baseSeed = NewRNG(0).Seed()
+	}
+
+	var results []SimulationResult
+	for _, settings := range sweepSettings(sweep) {
+		config := base.clone()
+		for field, value := range settings {
+			if err := config.applySetting(field, value); err != nil {
+				return nil, err
+			}
+		}
+		if err := config.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config for %v:\n%w", settings, err)
+		}
+		if config.Game.BotNumber <= 0 {
+			return nil, errors.New("Game.BotNumber must be positive to simulate games")
+		}
+
+		for i := 0; i < games; i++ {
+			gameConfig := config.clone()
+			gameConfig.Game.Seed = baseSeed + int64(i)
+			result, err := simulateGame(fmt.Sprintf("simulation-%d", i), gameConfig)
+			if err != nil {
+				return nil, err
+			}
+			result.Settings = settings
+			result.Game = i
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// simulateGame plays a game of bots from start to end without waiting for turns
+func simulateGame(id string, config *Config) (SimulationResult, error) {
+	// Simulated games are thrown away, so they neither persist events nor snapshots
+	config.EventLog.Backend = EventLogBackendMemory
+	config.Snapshot.Directory = ""
+
+	g, err := NewGame(id, config)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	g.quiet = true
+
+	result := SimulationResult{Seed: g.state.getSeed(), Bots: len(g.pMap.Players)}
+	for result.Turns < config.Game.MaxTurns && !g.state.isGameOver() && g.pMap.anyAlive() {
+		g.endTurn()
+		result.Turns++
+		result.ZombieTotals = append(result.ZombieTotals, g.gMap.zombieTotal())
+	}
+
+	result.Won = g.state.haveWon()
+	for _, player := range g.pMap.Players {
+		if player.Alive {
+			result.Survivors++
+		}
+	}
+	result.countDeaths(g.events.GetPlayerEvents("", EventFilters{EventType: EventPlayerDeath}))
+	return result, nil
+}
+
+// countDeaths adds up the causes of the players' deaths. Dead players stay on
+// their tile and can die again in later combats, so only the first death of
+// each player counts. Events come oldest first.
+func (r *SimulationResult) countDeaths(deaths []GameEvent) {
+	died := make(map[string]bool)
+	for _, event := range deaths {
+		if died[event.PlayerID] {
+			continue
+		}
+		died[event.PlayerID] = true
+		switch event.Details["reason"] {
+		case "starvation":
+			r.StarvationDeaths++
+		case "combat":
+			r.CombatDeaths++
+		}
+	}
+}
+
+// writeSimulationResults writes the results as a JSON array or as CSV with one
+// column per swept field. ZombieTotals are joined with ";" in CSV.
+func writeSimulationResults(w io.Writer, format string, results []SimulationResult) error {
+	switch format {
+	case SimulationFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	case SimulationFormatCSV:
+	default:
+		return fmt.Errorf("unknown format %q (use %q or %q)", format, SimulationFormatCSV, SimulationFormatJSON)
+	}
+
+	var fields []string
+	if len(results) > 0 {
+		for field := range results[0].Settings {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+	}
+
+	out := csv.NewWriter(w)
+	header := append(append([]string{}, fields...),
+		"game", "seed", "turns", "won", "bots", "survivors",
+		"starvation_deaths", "combat_deaths", "zombie_totals")
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, r := range results {
+		totals := make([]string, len(r.ZombieTotals))
+		for i, total := range r.ZombieTotals {
+			totals[i] = strconv.Itoa(total)
+		}
+		var row []string
+		for _, field := range fields {
+			row = append(row, r.Settings[field])
+		}
+		row = append(row,
+			strconv.Itoa(r.Game), strconv.FormatInt(r.Seed, 10), strconv.Itoa(r.Turns),
+			strconv.FormatBool(r.Won), strconv.Itoa(r.Bots), strconv.Itoa(r.Survivors),
+			strconv.Itoa(r.StarvationDeaths), strconv.Itoa(r.CombatDeaths),
+			strings.Join(totals, ";"))
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// sweepFlags collects repeated --sweep flags
+type sweepFlags []SweepParameter
+
+func (s *sweepFlags) String() string {
+	return fmt.Sprint(*s)
+}
+
+func (s *sweepFlags) Set(value string) error {
+	parameter, err := parseSweep(value)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, parameter)
+	return nil
+}
+
+// runSimulateCommand implements "gommo [--config file] simulate [flags]"
+func runSimulateCommand(args []string, base *Config) error {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	games := flags.Int("games", 10, "number of games per combination of sweep values")
+	format := flags.String("format", SimulationFormatCSV, "output format, csv or json")
+	outPath := flags.String("out", "", "file to write the results to, default stdout")
+	var sweep sweepFlags
+	flags.Var(&sweep, "sweep", "config field and values to try, e.g. Combat.ZombieCutoff=2,3,4 (repeatable)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *games <= 0 {
+		return fmt.Errorf("--games must be positive, got %d", *games)
+	}
+	if *format != SimulationFormatCSV && *format != SimulationFormatJSON {
+		return fmt.Errorf("unknown format %q (use %q or %q)", *format, SimulationFormatCSV, SimulationFormatJSON)
+	}
+
+	results, err := simulate(base, sweep, *games)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	return writeSimulationResults(out, *format, results)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	simulationConfig := func() *Config {
+		config := gameConfig.clone()
+		config.Map.Width = 12
+		config.Map.Height = 12
+		config.Game.Seed = 5
+		config.Game.BotNumber = 4
+		config.Game.MaxTurns = 30
+		return config
+	}
+
+	t.Run("runs every combination of the sweep", func(t *testing.T) {
+		sweep := []SweepParameter{
+			{Field: "Combat.ZombieCutoff", Values: []string{"2", "4"}},
+			{Field: "Game.VictoryNumber", Values: []string{"1", "2", "3"}},
+		}
+
+		results, err := simulate(simulationConfig(), sweep, 2)
+		require.NoError(t, err)
+
+		require.Len(t, results, 12)
+		for _, result := range results {
+			assert.Equal(t, 4, result.Bots)
+			assert.Positive(t, result.Turns)
+			assert.LessOrEqual(t, result.Turns, 30)
+			assert.Len(t, result.ZombieTotals, result.Turns)
+			assert.LessOrEqual(t, result.Survivors+result.StarvationDeaths+result.CombatDeaths, result.Bots)
+			assert.Contains(t, result.Settings, "Combat.ZombieCutoff")
+		}
+	})
+
+	t.Run("games end when every bot died or turns ran out", func(t *testing.T) {
+		results, err := simulate(simulationConfig(), nil, 3)
+		require.NoError(t, err)
+
+		for _, result := range results {
+			if !result.Won && result.Turns < 30 {
+				assert.Zero(t, result.Survivors, "A game should only stop early when won or everyone died")
+			}
+		}
+	})
+
+	t.Run("same seed gives the same results", func(t *testing.T) {
+		first, err := simulate(simulationConfig(), nil, 2)
+		require.NoError(t, err)
+		second, err := simulate(simulationConfig(), nil, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first[0].Seed, first[1].Seed)
+	})
+
+	t.Run("games play exactly MaxTurns ticks", func(t *testing.T) {
+		config := simulationConfig()
+		config.Game.MaxTurns = 3
+
+		result, err := simulateGame("turns", config)
+		require.NoError(t, err)
+
+		if !result.Won && result.Survivors > 0 {
+			assert.Equal(t, 3, result.Turns)
+		}
+		assert.LessOrEqual(t, result.Turns, 3)
+	})
+
+	t.Run("only the first death of a player counts", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.events.LogEvent(EventPlayerDeath, "p1", map[string]interface{}{"reason": "starvation"})
+		ts.game.events.LogEvent(EventPlayerDeath, "p1", map[string]interface{}{"reason": "combat"})
+
+		var result SimulationResult
+		result.countDeaths(ts.game.events.GetPlayerEvents("", EventFilters{EventType: EventPlayerDeath}))
+
+		assert.Equal(t, 1, result.StarvationDeaths)
+		assert.Equal(t, 0, result.CombatDeaths)
+	})
+
+	t.Run("invalid sweeps are rejected", func(t *testing.T) {
+		_, err := simulate(simulationConfig(), []SweepParameter{{Field: "Combat.Unknown", Values: []string{"1"}}}, 1)
+		assert.Error(t, err)
+
+		_, err = simulate(simulationConfig(), []SweepParameter{{Field: "Game.MaxTurns", Values: []string{"0"}}}, 1)
+		assert.ErrorContains(t, err, "Game.MaxTurns")
+
+		_, err = parseSweep("Combat.ZombieCutoff")
+		assert.Error(t, err)
+	})
+
+	t.Run("settings accept names like config files", func(t *testing.T) {
+		config := gameConfig.clone()
+
+		require.NoError(t, config.applySetting("Game.DefaultDirection", "north"))
+		require.NoError(t, config.applySetting("Combat.WeaponStrength", "7"))
+
+		assert.Equal(t, North, config.Game.DefaultDirection)
+		assert.Equal(t, 7, config.Combat.WeaponStrength)
+		assert.Equal(t, gameConfig.Combat.ZombieCutoff, config.Combat.ZombieCutoff, "Other fields should be kept")
+	})
+
+	t.Run("results are written as csv and json", func(t *testing.T) {
+		results, err := simulate(simulationConfig(), []SweepParameter{{Field: "Combat.ZombieCutoff", Values: []string{"2", "3"}}}, 1)
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, writeSimulationResults(&out, SimulationFormatCSV, results))
+		rows, err := csv.NewReader(&out).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, "Combat.ZombieCutoff", rows[0][0])
+		assert.Equal(t, "2", rows[1][0])
+
+		out.Reset()
+		require.NoError(t, writeSimulationResults(&out, SimulationFormatJSON, results))
+		var decoded []SimulationResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+		assert.Equal(t, results, decoded)
+	})
+}
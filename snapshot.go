@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotVersion is bumped whenever the snapshot layout changes incompatibly.
+// Version 1 stored hands as five slots with None marking the empty ones, version
+// 2 stores only the cards held. Older versions are still restored.
+const snapshotVersion = 2
+
+// Snapshot is the complete persisted state of one game
+type Snapshot struct {
+	Version  int
+	GameID   string
+	SavedAt  time.Time
+	Config   *Config
+	State    StateSnapshot
+	RNGState uint64
+	Map      MapSnapshot
+	Players  []PlayerSnapshot
+	Trades   []TradeOffer `json:",omitempty"` // open offers, carried out by the next trade phase
+	TradeID  int          `json:",omitempty"` // ID of the last offer made
+}
+
+type StateSnapshot struct {
+	TurnTimer      int
+	TurnElapsed    int
+	RemainingTurns int
+	HavePlayersWon bool
+	Seed           int64
+	Turn           int64
+	Paused         bool
+	Ended          bool
+}
+
+type MapSnapshot struct {
+	Width  int
+	Height int
+	Tiles  []TileSnapshot
+	Spawns [][2]int `json:",omitempty"`
+}
+
+// TileSnapshot stores the IDs of the players on the tile in their order on the tile
+type TileSnapshot struct {
+	X       int
+	Y       int
+	Terrain Terrain
+	Zombies int
+	Players []string `json:",omitempty"`
+}
+
+// PlayerSnapshot stores a player without its tile pointer, which is rebuilt from the tiles
+type PlayerSnapshot struct {
+	ID                     string
+	Name                   string
+	Direction              Direction
+	Play                   Card
+	Consume                Card
+	Discard                Card
+	Cards                  []Card
+	ResearchAcquisitionPos [][2]int
+	Alive                  bool
+	IsBot                  bool
+	Strategy               string           `json:",omitempty"`
+	Memory                 []RememberedTile `json:",omitempty"`
+}
+
+// snapshot captures the game. The caller holds g.mu.
+func (g *Game) snapshot() *Snapshot {
+	s := &Snapshot{
+		Version: snapshotVersion,
+		GameID:  g.ID,
+		SavedAt: time.Now(),
+		Config:  g.config,
+		State: StateSnapshot{
+			TurnTimer:      g.state.turnTimer,
+			TurnElapsed:    g.state.turnElapsed,
+			RemainingTurns: g.state.remainingTurns,
+			HavePlayersWon: g.state.havePlayersWon,
+			Seed:           g.state.seed,
+			Turn:           g.state.turn,
+			Paused:         g.state.paused,
+			Ended:          g.state.ended,
+		},
+		RNGState: g.rng.State(),
+		Map: MapSnapshot{
+			Width:  g.gMap.width,
+			Height: g.gMap.height,
+			Tiles:  make([]TileSnapshot, 0, g.gMap.width*g.gMap.height),
+			Spawns: g.gMap.spawns,
+		},
+	}
+
+	for x := range g.gMap.gMap {
+		for _, tile := range g.gMap.gMap[x] {
+			tileSnapshot := TileSnapshot{X: tile.XPos, Y: tile.YPos, Terrain: tile.Terrain, Zombies: tile.Zombies}
+			for _, playerPtr := range tile.playerPtrs {
+				tileSnapshot.Players = append(tileSnapshot.Players, playerPtr.ID)
+			}
+			s.Map.Tiles = append(s.Map.Tiles, tileSnapshot)
+		}
+	}
+
+	for _, player := range g.pMap.sortedPlayers() {
+		s.Players = append(s.Players, PlayerSnapshot{
+			ID:                     player.ID,
+			Name:                   player.Name,
+			Direction:              player.Direction,
+			Play:                   player.Play,
+			Consume:                player.Consume,
+			Discard:                player.Discard,
+			Cards:                  player.Cards,
+			ResearchAcquisitionPos: player.ResearchAcquisitionPos,
+			Alive:                  player.Alive,
+			IsBot:                  player.IsBot,
+			Strategy:               player.Strategy,
+			Memory:                 player.rememberedTiles(),
+		})
+	}
+	for _, offer := range g.trades.offers {
+		s.Trades = append(s.Trades, *offer)
+	}
+	s.TradeID = g.trades.nextID
+	return s
+}
+
+// saveSnapshot writes the game to <Snapshot.Directory>/<game ID>.json if snapshots
+// are enabled. The file is replaced atomically so a crash never leaves half a snapshot.
+// The caller holds g.mu.
+func (g *Game) saveSnapshot() {
+	dir := g.config.Snapshot.Directory
+	if dir == "" {
+		return
+	}
+	if err := writeSnapshot(filepath.Join(dir, g.ID+".json"), g.snapshot()); err != nil {
+		log.Printf("Failed to save snapshot of game %s: %v", g.ID, err)
+	}
+}
+
+func writeSnapshot(path string, s *Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadSnapshot reads a snapshot file written by saveSnapshot
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot %s: %w", path, err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing snapshot %s: %w", path, err)
+	}
+	if s.Version < 1 || s.Version > snapshotVersion {
+		return nil, fmt.Errorf("snapshot %s has version %d, expected 1 to %d", path, s.Version, snapshotVersion)
+	}
+	return &s, nil
+}
+
+// RestoreGame rebuilds a game from a snapshot, including the links between
+// Tile.playerPtrs and Player.CurrentTile.
+func RestoreGame(s *Snapshot) (*Game, error) {
+	if s.Config == nil {
+		return nil, fmt.Errorf("snapshot of game %s has no config", s.GameID)
+	}
+	if err := s.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("snapshot of game %s has an invalid config:\n%w", s.GameID, err)
+	}
+	if s.Map.Width <= 0 || s.Map.Height <= 0 || len(s.Map.Tiles) != s.Map.Width*s.Map.Height {
+		return nil, fmt.Errorf("snapshot of game %s has %d tiles for a %dx%d map",
+			s.GameID, len(s.Map.Tiles), s.Map.Width, s.Map.Height)
+	}
+
+	if s.Config.Server.IDSalt == "" {
+		s.Config.Server.IDSalt = newIDSalt()
+	}
+	events, err := newGameEventLogger(s.GameID, s.Config, true)
+	if err != nil {
+		return nil, fmt.Errorf("creating event log for game %s: %w", s.GameID, err)
+	}
+	g := &Game{
+		ID:     s.GameID,
+		config: s.Config,
+		rng:    NewRNG(s.State.Seed),
+		events: events,
+		wake:   make(chan struct{}, 1),
+	}
+	g.rng.SetState(s.RNGState)
+	g.state = gameState{
+		config:         s.Config,
+		turnTimer:      s.State.TurnTimer,
+		turnElapsed:    s.State.TurnElapsed,
+		remainingTurns: s.State.RemainingTurns,
+		havePlayersWon: s.State.HavePlayersWon,
+		seed:           s.State.Seed,
+		turn:           s.State.Turn,
+		paused:         s.State.Paused,
+		ended:          s.State.Ended,
+	}
+	g.events.BeginTurn(s.State.Turn)
+
+	g.pMap = NewPlayerMap(g)
+	for _, ps := range s.Players {
+		if len(ps.Cards) != len(ps.ResearchAcquisitionPos) {
+			return nil, fmt.Errorf("snapshot of game %s has %d cards but %d research positions for player %s",
+				s.GameID, len(ps.Cards), len(ps.ResearchAcquisitionPos), ps.ID)
+		}
+		cards, positions := []Card{}, [][2]int{}
+		for i, card := range ps.Cards {
+			if card == None {
+				if s.Version == 1 {
+					continue // an empty slot of a version 1 hand
+				}
+				return nil, fmt.Errorf("snapshot of game %s has an empty card in the hand of player %s", s.GameID, ps.ID)
+			}
+			cards = append(cards, card)
+			positions = append(positions, ps.ResearchAcquisitionPos[i])
+		}
+		g.pMap.Players[ps.ID] = &Player{
+			ID:                     ps.ID,
+			Name:                   ps.Name,
+			Direction:              ps.Direction,
+			Play:                   ps.Play,
+			Consume:                ps.Consume,
+			Discard:                ps.Discard,
+			Cards:                  cards,
+			ResearchAcquisitionPos: positions,
+			Alive:                  ps.Alive,
+			IsBot:                  ps.IsBot,
+			Strategy:               ps.Strategy,
+			memory:                 make(map[[2]int]Terrain, len(ps.Memory)),
+			game:                   g,
+		}
+		for _, tile := range ps.Memory {
+			g.pMap.Players[ps.ID].memory[[2]int{tile.X, tile.Y}] = tile.Terrain
+		}
+	}
+
+	g.gMap = newEmptyGameMap(g, s.Map.Width, s.Map.Height)
+	for _, spawn := range s.Map.Spawns {
+		if !g.gMap.isOnMap(spawn[0], spawn[1]) {
+			return nil, fmt.Errorf("snapshot of game %s has a spawn point off the map at %d,%d", s.GameID, spawn[0], spawn[1])
+		}
+	}
+	g.gMap.spawns = s.Map.Spawns
+	for _, ts := range s.Map.Tiles {
+		if ts.X < 0 || ts.X >= s.Map.Width || ts.Y < 0 || ts.Y >= s.Map.Height || g.gMap.gMap[ts.X][ts.Y] != nil {
+			return nil, fmt.Errorf("snapshot of game %s has an invalid or duplicate tile at %d,%d", s.GameID, ts.X, ts.Y)
+		}
+		tile := &Tile{Terrain: ts.Terrain, Zombies: ts.Zombies, playerPtrs: []*Player{}, XPos: ts.X, YPos: ts.Y, game: g}
+		for _, id := range ts.Players {
+			player := g.pMap.Players[id]
+			if player == nil || player.CurrentTile != nil {
+				return nil, fmt.Errorf("snapshot of game %s places unknown or duplicate player %s", s.GameID, id)
+			}
+			tile.addPlayer(player)
+			player.CurrentTile = tile
+		}
+		g.gMap.gMap[ts.X][ts.Y] = tile
+	}
+
+	for id, player := range g.pMap.Players {
+		if player.CurrentTile == nil {
+			return nil, fmt.Errorf("snapshot of game %s does not place player %s on a tile", s.GameID, id)
+		}
+	}
+
+	g.trades.nextID = s.TradeID
+	for _, offer := range s.Trades {
+		if g.pMap.Players[offer.From] == nil || g.pMap.Players[offer.To] == nil {
+			return nil, fmt.Errorf("snapshot of game %s has trade %s between unknown players", s.GameID, offer.ID)
+		}
+		offer := offer
+		g.trades.offers = append(g.trades.offers, &offer)
+	}
+	return g, nil
+}
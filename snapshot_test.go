@@ -0,0 +1,125 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("restored game matches the original", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		aliceID := ts.createPlayerAt(1, 1)
+		bobID := ts.playerMap.addPlayer("Bob", ts.gameMap.getTileFromPos(1, 1))
+		alice := ts.getPlayer(aliceID)
+		alice.setHand([]Card{Research, Weapon, Food})
+		alice.ResearchAcquisitionPos[0] = [2]int{4, 5}
+		alice.Direction = East
+		ts.setupTile(3, 3, City, 2)
+		ts.game.state.timerDown()
+
+		path := filepath.Join(t.TempDir(), "game.json")
+		require.NoError(t, writeSnapshot(path, ts.game.snapshot()))
+		snapshot, err := LoadSnapshot(path)
+		require.NoError(t, err)
+		restored, err := RestoreGame(snapshot)
+		require.NoError(t, err)
+
+		restoredAlice := restored.getPlayerOrNil(aliceID)
+		require.NotNil(t, restoredAlice)
+		assert.Equal(t, alice.Cards, restoredAlice.Cards)
+		assert.Equal(t, alice.ResearchAcquisitionPos, restoredAlice.ResearchAcquisitionPos)
+		assert.Equal(t, East, restoredAlice.Direction)
+		assert.Equal(t, ts.game.state.turnTimer, restored.state.turnTimer)
+		assert.Equal(t, 1, restored.state.elapsed())
+		assert.Equal(t, ts.game.state.getRemainingTurns(), restored.state.getRemainingTurns())
+		assert.Equal(t, ts.game.state.getSeed(), restored.state.getSeed())
+		assert.Equal(t, City, restored.gMap.getTileFromPos(3, 3).Terrain)
+		assert.Equal(t, 2, restored.gMap.getTileFromPos(3, 3).Zombies)
+
+		tile := restored.gMap.getTileFromPos(1, 1)
+		assert.Same(t, tile, restoredAlice.CurrentTile, "Player should point at the restored tile")
+		require.Len(t, tile.playerPtrs, 2)
+		assert.Same(t, restoredAlice, tile.playerPtrs[0], "Tile should point at the restored player")
+		assert.Equal(t, bobID, tile.playerPtrs[1].ID, "Player order on the tile should be kept")
+	})
+
+	t.Run("restored game continues identically", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		playerID := ts.playerMap.addPlayer("Alice", ts.gameMap.getNewPlayerEntryTile())
+		ts.game.tick()
+
+		restored, err := RestoreGame(roundTripSnapshot(t, ts.game.snapshot()))
+		require.NoError(t, err)
+
+		for _, game := range []*Game{ts.game, restored} {
+			game.getPlayerOrNil(playerID).Direction = North
+			game.tick()
+			game.tick()
+		}
+		assert.Equal(t, ts.game.snapshot().Map, restored.snapshot().Map)
+		assert.Equal(t, ts.game.snapshot().Players, restored.snapshot().Players)
+		assert.Equal(t, ts.game.rng.State(), restored.rng.State())
+	})
+
+	t.Run("snapshot is written after each turn when enabled", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Snapshot.Directory = t.TempDir()
+
+		ts.game.endTurn()
+
+		assert.FileExists(t, filepath.Join(ts.game.config.Snapshot.Directory, "test.json"))
+	})
+
+	t.Run("unknown version is rejected", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		snapshot := ts.game.snapshot()
+		snapshot.Version = snapshotVersion + 1
+		path := filepath.Join(t.TempDir(), "game.json")
+		require.NoError(t, writeSnapshot(path, snapshot))
+
+		_, err := LoadSnapshot(path)
+
+		assert.ErrorContains(t, err, "version")
+	})
+}
+
+// roundTripSnapshot writes and reads back a snapshot like a restart would
+func roundTripSnapshot(t *testing.T, s *Snapshot) *Snapshot {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, writeSnapshot(path, s))
+	loaded, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	return loaded
+}
+
+func TestSnapshotEndpoint(t *testing.T) {
+	t.Run("requires the admin token", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		as.registry.get(defaultGameID).config.Server.AdminToken = "secret"
+
+		assert.Equal(t, http.StatusUnauthorized, as.request(http.MethodGet, "/admin/snapshot", "").Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/snapshot", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		as.router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var snapshot Snapshot
+		as.decode(rec, &snapshot)
+		assert.Equal(t, defaultGameID, snapshot.GameID)
+		assert.Equal(t, snapshotVersion, snapshot.Version)
+	})
+
+	t.Run("is disabled without an admin token", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+
+		rec := as.request(http.MethodGet, "/admin/snapshot", "")
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tick notice types, also used as server-sent event names
+const (
+	TickStarted  = "tick_start"
+	TickFinished = "tick_end"
+)
+
+// PlayerUpdateEvent is the server-sent event name of a PlayerUpdate
+const PlayerUpdateEvent = "player_update"
+
+// TickNotice announces the start or end of a tick to stream subscribers
+type TickNotice struct {
+	Type           string
+	GameID         string
+	RemainingTurns int
+	TurnLength     int
+	HaveWon        bool
+}
+
+// PlayerUpdate is everything a client needs after a tick
+type PlayerUpdate struct {
+	Player       json.RawMessage
+	Surroundings Surroundings
+	Events       []GameEvent
+}
+
+// tickNotice describes the current state of the game. The caller holds g.mu.
+func (g *Game) tickNotice(noticeType string) TickNotice {
+	return TickNotice{
+		Type:           noticeType,
+		GameID:         g.ID,
+		RemainingTurns: g.state.getRemainingTurns(),
+		TurnLength:     g.config.Game.TurnLength,
+		HaveWon:        g.state.haveWon(),
+	}
+}
+
+// playerUpdate collects the player's state, surroundings and the events visible
+// to it since the given time. It returns false if the player does not exist, and
+// the time to pass as since for the next update.
+func (g *Game) playerUpdate(playerID string, since time.Time) (PlayerUpdate, time.Time, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	now := time.Now()
+	player := g.getPlayerOrNil(playerID)
+	if player == nil {
+		return PlayerUpdate{}, since, false
+	}
+	// The player is encoded while holding the lock since it points into the map
+	encoded, err := json.Marshal(player)
+	if err != nil {
+		return PlayerUpdate{}, since, false
+	}
+	return PlayerUpdate{
+		Player:       encoded,
+		Surroundings: g.playerSurroundings(*player),
+		Events:       g.events.GetPlayerEvents(playerID, EventFilters{Since: since}),
+	}, now, true
+}
+
+// tickBroadcaster fans tick notices out to stream subscribers.
+// It has its own lock because notices are published while the game lock is held.
+type tickBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan TickNotice]struct{}
+}
+
+func (b *tickBroadcaster) subscribe() chan TickNotice {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[chan TickNotice]struct{})
+	}
+	notices := make(chan TickNotice, 8)
+	b.subscribers[notices] = struct{}{}
+	return notices
+}
+
+func (b *tickBroadcaster) unsubscribe(notices chan TickNotice) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, notices)
+}
+
+// publish never blocks the tick: a subscriber that falls behind misses notices
+func (b *tickBroadcaster) publish(notice TickNotice) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for notices := range b.subscribers {
+		select {
+		case notices <- notice:
+		default:
+		}
+	}
+}
+
+// streamPlayerHandler pushes server-sent events to a player: the tick notices of
+// its game and, on connect and after every tick, a PlayerUpdate.
+func streamPlayerHandler(c *gin.Context) {
+	game := currentGame(c)
+	id := c.Param("id")
+
+	update, since, exists := game.playerUpdate(id, time.Time{})
+	if !exists {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	notices := game.ticks.subscribe()
+	defer game.ticks.unsubscribe(notices)
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent(PlayerUpdateEvent, update)
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case notice := <-notices:
+			c.SSEvent(notice.Type, notice)
+			if notice.Type == TickFinished {
+				if update, since, exists = game.playerUpdate(id, since); !exists {
+					return false
+				}
+				c.SSEvent(PlayerUpdateEvent, update)
+			}
+			return true
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readSSEvents collects server-sent event names from the stream until count are read
+func readSSEvents(t *testing.T, scanner *bufio.Scanner, count int) []string {
+	var names []string
+	for len(names) < count && scanner.Scan() {
+		if name, found := strings.CutPrefix(scanner.Text(), "event:"); found {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return names
+}
+
+func TestPlayerStream(t *testing.T) {
+	t.Run("pushes tick notices and player updates", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		server := httptest.NewServer(as.router)
+		defer server.Close()
+		game := as.registry.get(defaultGameID)
+		player := as.addPlayer("", "Alice")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/player/"+player.ID+"/stream", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+player.Token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/event-stream")
+
+		scanner := bufio.NewScanner(resp.Body)
+		assert.Equal(t, []string{PlayerUpdateEvent}, readSSEvents(t, scanner, 1), "Stream should start with the player's state")
+
+		game.endTurn()
+
+		assert.Equal(t, []string{TickStarted, TickFinished, PlayerUpdateEvent}, readSSEvents(t, scanner, 3))
+	})
+
+	t.Run("unknown player is rejected", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+
+		rec := as.request(http.MethodGet, "/player/missing/stream", "")
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("token can be passed as query parameter", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		player := as.addPlayer("", "Alice")
+
+		rec := as.request(http.MethodGet, "/player/"+player.ID+"/stream?token=wrong", "")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("query tokens are not logged", func(t *testing.T) {
+		line := requestLogFormatter(gin.LogFormatterParams{
+			Method: http.MethodGet,
+			Path:   "/player/p1/stream?since=5&token=secret",
+		})
+
+		assert.NotContains(t, line, "secret")
+		assert.Contains(t, line, "since=5")
+		assert.Contains(t, line, "token=REDACTED")
+	})
+}
+
+func TestPlayerUpdate(t *testing.T) {
+	t.Run("only includes events since the last update", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		playerID := ts.createPlayerAt(1, 1)
+
+		first, since, exists := ts.game.playerUpdate(playerID, time.Time{})
+		require.True(t, exists)
+		assert.NotEmpty(t, first.Events, "First update should contain the join event")
+
+		ts.getPlayer(playerID).Direction = East
+		ts.game.tick()
+		second, _, _ := ts.game.playerUpdate(playerID, since)
+
+		for _, event := range second.Events {
+			assert.NotEqual(t, EventPlayerJoin, event.Type, "Join event was already delivered")
+		}
+		assert.NotEmpty(t, second.Events, "Tick events should be delivered")
+	})
+}
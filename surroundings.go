@@ -21,3 +21,27 @@ type Surroundings struct {
 	SS MapPiece
 	SE MapPiece
 }
+
+// inDirection returns the piece a player on the center tile reaches by moving in d
+func (s Surroundings) inDirection(d Direction) MapPiece {
+	switch d {
+	case North:
+		return s.NN
+	case East:
+		return s.EE
+	case South:
+		return s.SS
+	case West:
+		return s.WW
+	}
+	return s.CE
+}
+
+// terrain returns the terrain of the piece, treating unknown names as Edge
+func (m MapPiece) terrain() Terrain {
+	var t Terrain
+	if err := t.UnmarshalText([]byte(m.TileType)); err != nil {
+		return Edge
+	}
+	return t
+}
@@ -1,5 +1,12 @@
 package main
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type Terrain int
 
 const (
@@ -24,6 +31,32 @@ func (t Terrain) isCity() bool {
 	return t == City
 }
 
-func (t Terrain) offersResource() (Card, int) {
-	return gameConfig.TerrainResources[t].givesCard, gameConfig.TerrainResources[t].amount
+// UnmarshalText accepts terrain names case-insensitively as well as their numeric
+// value, so Terrain can be used as a map key in config files.
+func (t *Terrain) UnmarshalText(text []byte) error {
+	name := string(text)
+	for _, terrain := range terrainTypes {
+		if strings.EqualFold(terrain.toString(), name) {
+			*t = terrain
+			return nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= int(Forest) && n <= int(Edge) {
+		*t = Terrain(n)
+		return nil
+	}
+	return fmt.Errorf("unknown terrain %q", name)
+}
+
+// UnmarshalJSON accepts a terrain name or the plain number Terrain is encoded as
+func (t *Terrain) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return t.UnmarshalText([]byte(name))
+	}
+	return t.UnmarshalText(data)
+}
+
+func (t Terrain) offersResource(config *Config) (Card, int) {
+	return config.TerrainResources[t].GivesCard, config.TerrainResources[t].Amount
 }
@@ -11,15 +11,17 @@ type Tile struct {
 	playerPtrs []*Player
 	XPos       int
 	YPos       int
+	game       *Game
+	retreating []*Player // players falling back after a lost fight, see withdrawRetreats
 }
 
-func tileWorker(t *Tile, wg *sync.WaitGroup) {
+func tileWorker(t *Tile, dice *RNG, wg *sync.WaitGroup) {
 	defer wg.Done()
-	t.resolveCombat()
+	t.resolveCombat(dice)
 	//fmt.Println("Worker started with ", t.toString())
 }
 
-func (t *Tile) resolveCombat() {
+func (t *Tile) resolveCombat(dice *RNG) {
 	// Skip if no players on this tile
 	if len(t.playerPtrs) == 0 {
 		return
@@ -31,109 +33,132 @@ func (t *Tile) resolveCombat() {
 		playerIDs = append(playerIDs, p.ID)
 	}
 
-	eventLogger.LogEvent(EventCombatStart, "", map[string]interface{}{
+	t.game.events.LogEvent(EventCombatStart, "", map[string]interface{}{
 		"x":              t.XPos,
 		"y":              t.YPos,
 		"players":        playerIDs,
 		"zombies_before": t.Zombies,
 	})
 
-	totalPlayerStrength := 0
-	playerStrengths := make(map[string]int)
+	input := t.combatInput()
+	roller := &liveRoller{game: t.game, dice: dice, fighters: input.Fighters, rolls: make([][]int, len(input.Fighters))}
+	outcome := t.game.combatResolver().Resolve(input, roller)
 
-	// Calculate each player's strength
-	for _, playerPtr := range t.playerPtrs {
-		var player = *playerPtr
-		var strength = 0
-		weaponIndex, hasCard := hasCardWhere(player.Cards[:], Weapon)
-
-		if player.Play == Weapon && hasCard {
-			// Player used a weapon card
-			strength = gameConfig.Combat.WeaponStrength
-			player.Cards[weaponIndex] = None
-
-			eventLogger.LogEvent(EventCardUsed, player.ID, map[string]interface{}{
-				"card":      Weapon.String(),
-				"card_slot": weaponIndex,
-				"x":         t.XPos,
-				"y":         t.YPos,
-				"strength":  strength,
-			})
-		} else {
-			// Player rolls dice
-			strength = rollDice(player.ID)
-		}
-
-		playerStrengths[player.ID] = strength
+	// Played cards are used up
+	totalPlayerStrength := 0
+	dicePlayed := []string{}
+	for i, playerPtr := range t.playerPtrs {
+		strength := outcome.Strengths[i]
 		totalPlayerStrength += strength
+		switch input.Fighters[i].Play {
+		case Weapon:
+			t.useWeapon(playerPtr, strength)
+		case Dice:
+			t.useDice(playerPtr, roller.rolls[i], strength)
+			dicePlayed = append(dicePlayed, playerPtr.ID)
+		}
 	}
 
-	// Determine combat outcome
-	combatWon := totalPlayerStrength > t.Zombies
-	zombiesKilled := 0
+	zombiesBefore := t.Zombies
+	t.Zombies -= outcome.ZombiesKilled
 	playersKilled := 0
-
-	if combatWon {
-		// Players win - kill all zombies
-		zombiesKilled = t.Zombies
-		t.Zombies = 0
-	} else {
-		// Zombies win - kill all players
-		playersKilled = len(t.playerPtrs)
-		for _, playerPtr := range t.playerPtrs {
-			playerPtr.Alive = false
-
-			// Log player death in combat
-			eventLogger.LogEvent(EventPlayerDeath, playerPtr.ID, map[string]interface{}{
-				"reason":   "combat",
-				"x":        t.XPos,
-				"y":        t.YPos,
-				"zombies":  t.Zombies,
-				"strength": playerStrengths[playerPtr.ID],
-			})
+	playersRetreated := []string{}
+	for i, playerPtr := range t.playerPtrs {
+		if outcome.Retreated[i] {
+			playersRetreated = append(playersRetreated, playerPtr.ID)
+			t.retreating = append(t.retreating, playerPtr)
 		}
+		if !outcome.Killed[i] {
+			continue
+		}
+		playersKilled++
+		playerPtr.Alive = false
+
+		// Log player death in combat
+		t.game.events.LogEvent(EventPlayerDeath, playerPtr.ID, map[string]interface{}{
+			"reason":   "combat",
+			"x":        t.XPos,
+			"y":        t.YPos,
+			"zombies":  t.Zombies,
+			"strength": outcome.Strengths[i],
+		})
+	}
 
-		// Zombies multiply from dead players
-		t.addZombies(playersKilled)
+	// Zombies multiply from dead players
+	t.addZombies(playersKilled)
+	if outcome.Won {
+		t.dropDice(dice)
 	}
 
 	// Log combat result
-	eventLogger.LogEvent(EventCombatResult, "", map[string]interface{}{
-		"x":               t.XPos,
-		"y":               t.YPos,
-		"players":         playerIDs,
-		"player_strength": totalPlayerStrength,
-		"zombies_before":  t.Zombies + zombiesKilled,
-		"zombies_after":   t.Zombies,
-		"combat_won":      combatWon,
-		"zombies_killed":  zombiesKilled,
-		"players_killed":  playersKilled,
+	t.game.events.LogEvent(EventCombatResult, "", map[string]interface{}{
+		"x":                 t.XPos,
+		"y":                 t.YPos,
+		"players":           playerIDs,
+		"player_strength":   totalPlayerStrength,
+		"zombies_before":    zombiesBefore,
+		"zombies_after":     t.Zombies,
+		"combat_won":        outcome.Won,
+		"zombies_killed":    outcome.ZombiesKilled,
+		"players_killed":    playersKilled,
+		"players_retreated": playersRetreated,
+		"dice_played":       dicePlayed,
+		"dice_effect":       t.game.config.Combat.DiceEffect,
+		"resolver":          t.game.config.Combat.Resolver,
+	})
+}
+
+// withdrawRetreats moves the players who retreated from the fight on the tile
+// back to the tile they came from
+func (t *Tile) withdrawRetreats() {
+	for _, player := range t.retreating {
+		t.removePlayer(player)
+		player.cameFrom.addPlayer(player)
+		player.CurrentTile = player.cameFrom
+
+		t.game.events.LogEvent(EventPlayerMove, player.ID, map[string]interface{}{
+			"from_x": t.XPos,
+			"from_y": t.YPos,
+			"to_x":   player.cameFrom.XPos,
+			"to_y":   player.cameFrom.YPos,
+			"reason": "retreated",
+		})
+	}
+	t.retreating = nil
+}
+
+// useWeapon removes the weapon the player fought with from its hand
+func (t *Tile) useWeapon(player *Player, strength int) {
+	weaponIndex, _ := hasCardWhere(player.Cards, Weapon)
+	player.removeCard(weaponIndex)
+
+	t.game.events.LogEvent(EventCardUsed, player.ID, map[string]interface{}{
+		"card":      Weapon.String(),
+		"card_slot": weaponIndex,
+		"x":         t.XPos,
+		"y":         t.YPos,
+		"strength":  strength,
 	})
 }
 
 func (t Tile) giveResources() {
 	for _, playerPtr := range t.playerPtrs {
-		cards, amount := t.Terrain.offersResource()
+		cards, amount := t.Terrain.offersResource(t.game.config)
 		for i := 0; i < amount; i++ {
-			emptyIndex, hasSpace := hasCardWhere(playerPtr.Cards[:], None)
-			if !hasSpace {
-				continue
-			}
-			playerPtr.Cards[emptyIndex] = cards
-			// Track where research cards are acquired
-			if cards == Research {
-				playerPtr.ResearchAcquisitionPos[emptyIndex] = [2]int{t.XPos, t.YPos}
-			}
+			playerPtr.addCard(cards, t.XPos, t.YPos)
+		}
+		if chance := t.game.config.TerrainResources[t.Terrain].DiceChance; chance > 0 && t.game.rng.Intn(100) < chance {
+			playerPtr.drawCard(Dice, "terrain")
 		}
 	}
 }
 
 func (t Tile) isSpreader() bool {
-	return t.Terrain.isCity() || t.Zombies >= gameConfig.Combat.ZombieCutoff
+	return t.Terrain.isCity() || t.Zombies >= t.game.config.Combat.ZombieCutoff
 }
 
 func (t *Tile) spreadTo() {
-	if t.Zombies < gameConfig.Combat.ZombieCutoff {
+	if t.Zombies < t.game.config.Combat.ZombieCutoff {
 		t.Zombies++
 	}
 }
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TradeOffer is a proposal to give a card to another player on the same tile in
+// exchange for one of theirs. Want is None for a gift. A player has at most one
+// open offer to each other player. Offers are made between ticks and carried out
+// by the trade phase of the next tick if the other player accepted; offers not
+// accepted by then expire.
+type TradeOffer struct {
+	ID       string
+	From     string
+	To       string
+	Give     Card
+	Want     Card
+	Accepted bool
+}
+
+// tradeBook holds the open offers of a game in the order they were made
+type tradeBook struct {
+	offers []*TradeOffer
+	nextID int
+}
+
+// TradeError explains why an offer could not be made, accepted or carried out.
+// Code is the error code of the API response.
+type TradeError struct {
+	Code    string
+	Message string
+}
+
+func (e TradeError) Error() string {
+	return e.Message
+}
+
+// sharesTileWith is true if both players stand on the same tile
+func (p *Player) sharesTileWith(other *Player) bool {
+	_, found := p.CurrentTile.findPlayerPtrIndex(other)
+	return found
+}
+
+// offerTrade records an offer from one player to another, replacing an earlier
+// offer between them. The caller holds g.mu.
+func (g *Game) offerTrade(from *Player, toID string, give, want Card) (*TradeOffer, error) {
+	to := g.getPlayerOrNil(toID)
+	switch {
+	case to == nil || to == from:
+		return nil, TradeError{"unknown_player", fmt.Sprintf("Player %s cannot be traded with", toID)}
+	case !from.Alive || !to.Alive:
+		return nil, TradeError{"player_dead", "Only living players trade"}
+	case !from.sharesTileWith(to):
+		return nil, TradeError{"not_on_same_tile", "Players trade only with players on their tile"}
+	case give == None:
+		return nil, TradeError{"invalid_card", "An offer has to give a card"}
+	case from.countCards(give) == 0:
+		return nil, TradeError{"card_not_held", fmt.Sprintf("You hold no %s card", give)}
+	}
+
+	g.trades.nextID++
+	offer := &TradeOffer{ID: strconv.Itoa(g.trades.nextID), From: from.ID, To: to.ID, Give: give, Want: want}
+	if existing := g.findTrade(from.ID, to.ID); existing != nil {
+		*existing = *offer
+		offer = existing
+	} else {
+		g.trades.offers = append(g.trades.offers, offer)
+	}
+	g.events.LogEvent(EventTradeOffered, from.ID, offer.details(from.CurrentTile))
+	return offer, nil
+}
+
+// acceptTrade accepts the offer made to the player by another. The caller holds g.mu.
+func (g *Game) acceptTrade(player *Player, fromID string) (*TradeOffer, error) {
+	offer := g.findTrade(fromID, player.ID)
+	if offer == nil {
+		return nil, TradeError{"trade_not_found", fmt.Sprintf("Player %s made you no offer", fromID)}
+	}
+	if offer.Want != None && player.countCards(offer.Want) == 0 {
+		return nil, TradeError{"card_not_held", fmt.Sprintf("You hold no %s card", offer.Want)}
+	}
+	offer.Accepted = true
+	g.events.LogEvent(EventTradeAccepted, player.ID, offer.details(player.CurrentTile))
+	return offer, nil
+}
+
+// cancelTrade withdraws the player's offer to another player and declines the
+// other's offer to the player. The caller holds g.mu.
+func (g *Game) cancelTrade(player *Player, otherID string) error {
+	var open []*TradeOffer
+	cancelled := false
+	for _, offer := range g.trades.offers {
+		if (offer.From == player.ID && offer.To == otherID) || (offer.From == otherID && offer.To == player.ID) {
+			g.events.LogEvent(EventTradeFailed, player.ID, offer.failure(player.CurrentTile, "cancelled"))
+			cancelled = true
+			continue
+		}
+		open = append(open, offer)
+	}
+	if !cancelled {
+		return TradeError{"trade_not_found", fmt.Sprintf("There is no offer between you and player %s", otherID)}
+	}
+	g.trades.offers = open
+	return nil
+}
+
+// findTrade returns the open offer from one player to another, or nil
+func (g *Game) findTrade(fromID, toID string) *TradeOffer {
+	for _, offer := range g.trades.offers {
+		if offer.From == fromID && offer.To == toID {
+			return offer
+		}
+	}
+	return nil
+}
+
+// tradesOf lists the open offers made by or to the player
+func (g *Game) tradesOf(playerID string) []TradeOffer {
+	trades := []TradeOffer{}
+	for _, offer := range g.trades.offers {
+		if offer.From == playerID || offer.To == playerID {
+			trades = append(trades, *offer)
+		}
+	}
+	return trades
+}
+
+// runTrades carries out the accepted offers in the order they were made and
+// drops all open offers
+func (g *Game) runTrades() {
+	offers := g.trades.offers
+	g.trades.offers = nil
+	for _, offer := range offers {
+		if !offer.Accepted {
+			continue
+		}
+		from, to := g.getPlayerOrNil(offer.From), g.getPlayerOrNil(offer.To)
+		if err := g.swapCards(offer, from, to); err != nil {
+			g.events.LogEvent(EventTradeFailed, offer.From, offer.failure(from.CurrentTile, err.Code))
+			continue
+		}
+		g.events.LogEvent(EventTradeCompleted, offer.From, offer.details(from.CurrentTile))
+	}
+}
+
+// swapCards moves the offered card to the receiving player and the wanted card,
+// if any, back. Cards keep the position they were acquired at.
+func (g *Game) swapCards(offer *TradeOffer, from, to *Player) *TradeError {
+	switch {
+	case !from.Alive || !to.Alive:
+		return &TradeError{"player_dead", "Only living players trade"}
+	case !from.sharesTileWith(to):
+		return &TradeError{"not_on_same_tile", "The players are no longer on the same tile"}
+	case from.countCards(offer.Give) == 0 || (offer.Want != None && to.countCards(offer.Want) == 0):
+		return &TradeError{"card_not_held", "A traded card is no longer held"}
+	case offer.Want == None && to.getHandSize() >= to.handCapacity():
+		return &TradeError{"hand_full", "The receiving hand is full"}
+	}
+
+	given, givenPos := from.takeCard(offer.Give)
+	to.putCard(given, givenPos)
+	if offer.Want != None {
+		wanted, wantedPos := to.takeCard(offer.Want)
+		from.putCard(wanted, wantedPos)
+	}
+	return nil
+}
+
+// takeCard removes the first card of the given type and returns it with the
+// position it was acquired at
+func (p *Player) takeCard(card Card) (Card, [2]int) {
+	slot, _ := hasCardWhere(p.Cards, card)
+	pos := p.ResearchAcquisitionPos[slot]
+	return p.removeCard(slot), pos
+}
+
+// putCard adds a card with the position it was acquired at to the end of the hand
+func (p *Player) putCard(card Card, pos [2]int) {
+	p.Cards = append(p.Cards, card)
+	p.ResearchAcquisitionPos = append(p.ResearchAcquisitionPos, pos)
+}
+
+// details describes the offer for trade events, which both players see
+func (o *TradeOffer) details(tile *Tile) map[string]interface{} {
+	return map[string]interface{}{
+		"trade_id": o.ID,
+		"from":     o.From,
+		"to":       o.To,
+		"give":     o.Give.String(),
+		"want":     o.Want.String(),
+		"x":        tile.XPos,
+		"y":        tile.YPos,
+	}
+}
+
+// failure describes an offer that was cancelled or could not be carried out
+func (o *TradeOffer) failure(tile *Tile, reason string) map[string]interface{} {
+	details := o.details(tile)
+	details["reason"] = reason
+	return details
+}
+
+// TradeRequest is the body of PUT /player/:id/trades/:other. Want may be omitted for a gift.
+type TradeRequest struct {
+	Give *Card
+	Want *Card
+}
+
+// sendTradeError responds with the code of a TradeError
+func sendTradeError(c *gin.Context, err error) {
+	var tradeErr TradeError
+	if !errors.As(err, &tradeErr) {
+		sendErrorResponse(c, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	status := http.StatusBadRequest
+	if tradeErr.Code == "trade_not_found" {
+		status = http.StatusNotFound
+	}
+	sendErrorResponse(c, status, tradeErr.Code, tradeErr.Message)
+}
+
+// getTradesHandler lists the open offers made by or to the player
+func getTradesHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.RLock()
+	defer game.mu.RUnlock()
+	sendSuccessResponse(c, http.StatusOK, gin.H{"trades": game.tradesOf(c.Param("id"))})
+}
+
+// offerTradeHandler offers the other player on the same tile a card, replacing
+// an earlier offer to that player
+func offerTradeHandler(c *gin.Context) {
+	var request TradeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_body", err.Error())
+		return
+	}
+	if request.Give == nil {
+		sendErrorResponse(c, http.StatusBadRequest, "invalid_body", "Give names the card to offer")
+		return
+	}
+	want := None
+	if request.Want != nil {
+		want = *request.Want
+	}
+
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	offer, err := game.offerTrade(game.getPlayerOrNil(c.Param("id")), c.Param("other"), *request.Give, want)
+	if err != nil {
+		sendTradeError(c, err)
+		return
+	}
+	sendSuccessResponse(c, http.StatusCreated, gin.H{"trade": offer})
+}
+
+// acceptTradeHandler accepts the offer the other player made to the player
+func acceptTradeHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	offer, err := game.acceptTrade(game.getPlayerOrNil(c.Param("id")), c.Param("other"))
+	if err != nil {
+		sendTradeError(c, err)
+		return
+	}
+	sendSuccessResponse(c, http.StatusOK, gin.H{"trade": offer})
+}
+
+// cancelTradeHandler withdraws or declines the offers between the player and the other player
+func cancelTradeHandler(c *gin.Context) {
+	game := currentGame(c)
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	if err := game.cancelTrade(game.getPlayerOrNil(c.Param("id")), c.Param("other")); err != nil {
+		sendTradeError(c, err)
+		return
+	}
+	sendSuccessResponse(c, http.StatusOK, nil)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrade(t *testing.T) {
+	// setupTraders puts two players on the tile at 1, 1
+	setupTraders := func(t *testing.T) (*TestSuite, *Player, *Player) {
+		ts := setupTestSuite(t)
+		alice := ts.getPlayer(ts.createPlayerAt(1, 1))
+		bob := ts.getPlayer(ts.playerMap.addPlayer("Bob", ts.gameMap.getTileFromPos(1, 1)))
+		return ts, alice, bob
+	}
+
+	t.Run("accepted offer swaps the cards in the trade phase", func(t *testing.T) {
+		ts, alice, bob := setupTraders(t)
+		alice.setHand([]Card{Food, Research})
+		alice.ResearchAcquisitionPos[1] = [2]int{7, 8}
+		bob.setHand([]Card{Weapon})
+
+		_, err := ts.game.offerTrade(alice, bob.ID, Research, Weapon)
+		require.NoError(t, err)
+		_, err = ts.game.acceptTrade(bob, alice.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []Card{Food, Research}, alice.Cards, "cards move only in the trade phase")
+
+		ts.game.runTrades()
+
+		assert.Equal(t, []Card{Food, Weapon}, alice.Cards)
+		assert.Equal(t, []Card{Research}, bob.Cards)
+		assert.Equal(t, [][2]int{{7, 8}}, bob.ResearchAcquisitionPos, "research keeps the lab it came from")
+		assert.Empty(t, ts.game.tradesOf(alice.ID))
+
+		for _, eventType := range []EventType{EventTradeOffered, EventTradeAccepted, EventTradeCompleted} {
+			assert.Len(t, ts.game.events.GetPlayerEvents(alice.ID, EventFilters{EventType: eventType}), 1, eventType)
+			assert.Len(t, ts.game.events.GetPlayerEvents(bob.ID, EventFilters{EventType: eventType}), 1, eventType)
+		}
+	})
+
+	t.Run("traded research still counts for a win at another lab", func(t *testing.T) {
+		ts, alice, bob := setupTraders(t)
+		ts.setupTile(1, 1, Laboratory, 0)
+		alice.setHand([]Card{Research})
+		alice.ResearchAcquisitionPos[0] = [2]int{5, 5}
+		bob.setHand([]Card{Research})
+		bob.ResearchAcquisitionPos[0] = [2]int{1, 1}
+
+		_, err := ts.game.offerTrade(alice, bob.ID, Research, None)
+		require.NoError(t, err)
+		_, err = ts.game.acceptTrade(bob, alice.ID)
+		require.NoError(t, err)
+		ts.game.runTrades()
+
+		assert.Empty(t, alice.Cards)
+		assert.Equal(t, 1, bob.usableResearchAt(1, 1))
+	})
+
+	t.Run("offers not accepted expire", func(t *testing.T) {
+		ts, alice, bob := setupTraders(t)
+
+		_, err := ts.game.offerTrade(alice, bob.ID, Food, None)
+		require.NoError(t, err)
+		ts.game.runTrades()
+
+		assert.Equal(t, []Card{Food, Wood, Wood}, alice.Cards)
+		assert.Empty(t, ts.game.tradesOf(bob.ID))
+	})
+
+	t.Run("offers need a partner on the same tile and the card", func(t *testing.T) {
+		ts, alice, bob := setupTraders(t)
+		carol := ts.getPlayer(ts.playerMap.addPlayer("Carol", ts.gameMap.getTileFromPos(2, 2)))
+
+		_, err := ts.game.offerTrade(alice, carol.ID, Food, None)
+		assert.Equal(t, "not_on_same_tile", err.(TradeError).Code)
+		_, err = ts.game.offerTrade(alice, bob.ID, Weapon, None)
+		assert.Equal(t, "card_not_held", err.(TradeError).Code)
+		_, err = ts.game.offerTrade(alice, alice.ID, Food, None)
+		assert.Equal(t, "unknown_player", err.(TradeError).Code)
+	})
+
+	t.Run("trade fails if a player moved away", func(t *testing.T) {
+		ts, alice, bob := setupTraders(t)
+		_, err := ts.game.offerTrade(alice, bob.ID, Food, Wood)
+		require.NoError(t, err)
+		_, err = ts.game.acceptTrade(bob, alice.ID)
+		require.NoError(t, err)
+		ts.movePlayerTo(bob, 3, 3)
+
+		ts.game.runTrades()
+
+		assert.Equal(t, []Card{Food, Wood, Wood}, alice.Cards)
+		failed := ts.game.events.GetPlayerEvents(bob.ID, EventFilters{EventType: EventTradeFailed})
+		require.Len(t, failed, 1)
+		assert.Equal(t, "not_on_same_tile", failed[0].Details["reason"])
+	})
+
+	t.Run("open offers survive a restore", func(t *testing.T) {
+		ts, alice, bob := setupTraders(t)
+		_, err := ts.game.offerTrade(alice, bob.ID, Food, Wood)
+		require.NoError(t, err)
+		_, err = ts.game.acceptTrade(bob, alice.ID)
+		require.NoError(t, err)
+
+		restored, err := RestoreGame(roundTripSnapshot(t, ts.game.snapshot()))
+		require.NoError(t, err)
+
+		offer := restored.findTrade(alice.ID, bob.ID)
+		require.NotNil(t, offer)
+		assert.True(t, offer.Accepted)
+		restored.runTrades()
+		assert.Equal(t, []Card{Wood, Wood, Wood}, restored.pMap.Players[alice.ID].Cards)
+
+		next, err := restored.offerTrade(restored.pMap.Players[bob.ID], alice.ID, Wood, None)
+		require.NoError(t, err)
+		assert.Equal(t, "2", next.ID, "offer IDs continue after a restore")
+	})
+
+	t.Run("trade phase runs before resources", func(t *testing.T) {
+		phases := DefaultPhases()
+		indexOf := func(name string) int {
+			for i, phase := range phases {
+				if phase == name {
+					return i
+				}
+			}
+			return -1
+		}
+
+		assert.Less(t, indexOf(PhaseTrade), indexOf(PhaseResources))
+		assert.Less(t, indexOf(PhaseTrade), indexOf(PhaseMove), "players trade where they made the offer")
+	})
+
+	t.Run("trade endpoints", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		alice := as.addPlayer("", "alice")
+		bob := as.addPlayer("", "bob")
+		game := as.registry.get(defaultGameID)
+		game.pMap.teleport(game.pMap.Players[bob.ID], game.pMap.Players[alice.ID].CurrentTile.XPos, game.pMap.Players[alice.ID].CurrentTile.YPos)
+
+		offerPath := "/player/" + alice.ID + "/trades/" + bob.ID
+		rec := as.requestWithToken(http.MethodPut, offerPath, `{"Give": "Wood"}`, alice.Token)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		rec = as.requestWithToken(http.MethodPut, offerPath, `{"Give": "Wood", "Want": "Food"}`, alice.Token)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		rec = as.requestWithToken(http.MethodGet, "/player/"+bob.ID+"/trades", "", bob.Token)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var listed struct{ Trades []TradeOffer }
+		as.decode(rec, &listed)
+		require.Len(t, listed.Trades, 1, "a new offer replaces the earlier one")
+		assert.Equal(t, Food, listed.Trades[0].Want)
+
+		assert.Equal(t, http.StatusNotFound,
+			as.requestWithToken(http.MethodPut, "/player/"+alice.ID+"/trades/"+bob.ID+"/accept", "", alice.Token).Code,
+			"only the receiving player accepts")
+		rec = as.requestWithToken(http.MethodPut, "/player/"+bob.ID+"/trades/"+alice.ID+"/accept", "", bob.Token)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.True(t, game.findTrade(alice.ID, bob.ID).Accepted)
+
+		rec = as.requestWithToken(http.MethodDelete, "/player/"+bob.ID+"/trades/"+alice.ID, "", bob.Token)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Nil(t, game.findTrade(alice.ID, bob.ID))
+
+		assert.Equal(t, http.StatusBadRequest,
+			as.requestWithToken(http.MethodPut, offerPath, `{"Want": "Food"}`, alice.Token).Code)
+	})
+}
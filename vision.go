@@ -0,0 +1,127 @@
+package main
+
+import "sort"
+
+// States of a tile in a player's view
+const (
+	TileVisible    = "visible"    // in sight, with live zombie and player counts
+	TileRemembered = "remembered" // seen before, only the terrain is known
+	TileUnknown    = "unknown"    // never seen, TileType is empty
+)
+
+// VisionTile is one tile of a View. DX and DY are relative to the player's
+// tile, negative DX is west and negative DY is north.
+type VisionTile struct {
+	DX    int
+	DY    int
+	State string
+	MapPiece
+}
+
+// View is the grid of tiles around a player. Tiles is indexed [DY+Radius][DX+Radius],
+// so rows run from north to south and every row from west to east. Radius is
+// the configured vision radius, or the current one if terrain extends it.
+// Tiles within VisionRadius steps, counting diagonal steps as one, are visible.
+// Tiles off the map are visible as Edge.
+type View struct {
+	Radius       int
+	VisionRadius int
+	Tiles        [][]VisionTile
+}
+
+// visionRadius is how far the player sees from its current tile
+func (g *Game) visionRadius(player *Player) int {
+	radius := g.config.Player.VisionRadius + g.config.Player.TerrainVision[player.CurrentTile.Terrain]
+	return max(radius, 0)
+}
+
+// inSight is true if the tile dx, dy away is within radius steps
+func inSight(dx, dy, radius int) bool {
+	return max(dx, -dx, dy, -dy) <= radius
+}
+
+// playerView returns the grid the player sees, filling the fog with the tiles it
+// remembers. Dead players see a laboratory surrounded by edges, like playerSurroundings.
+func (g *Game) playerView(player *Player) View {
+	vision := g.visionRadius(player)
+	radius := max(g.config.Player.VisionRadius, vision)
+	view := View{Radius: radius, VisionRadius: vision, Tiles: make([][]VisionTile, 2*radius+1)}
+	for dy := -radius; dy <= radius; dy++ {
+		row := make([]VisionTile, 2*radius+1)
+		for dx := -radius; dx <= radius; dx++ {
+			row[dx+radius] = g.visionTile(player, dx, dy, vision)
+		}
+		view.Tiles[dy+radius] = row
+	}
+	return view
+}
+
+func (g *Game) visionTile(player *Player, dx, dy, vision int) VisionTile {
+	tile := VisionTile{DX: dx, DY: dy, State: TileVisible}
+	if !player.Alive {
+		tile.TileType = Edge.toString()
+		if dx == 0 && dy == 0 {
+			tile.TileType = Laboratory.toString()
+		}
+		return tile
+	}
+
+	x, y := player.CurrentTile.XPos+dx, player.CurrentTile.YPos+dy
+	switch {
+	case inSight(dx, dy, vision):
+		tile.MapPiece = g.gMap.getTileFromPos(x, y).getMapPiece()
+	case !g.gMap.isOnMap(x, y):
+		// The edge of the world is no secret
+		tile.TileType = Edge.toString()
+	default:
+		if terrain, seen := player.memory[[2]int{x, y}]; seen {
+			tile.State = TileRemembered
+			tile.TileType = terrain.toString()
+		} else {
+			tile.State = TileUnknown
+		}
+	}
+	return tile
+}
+
+// rememberVisibleTiles adds the terrain of every tile the player currently sees
+// to its memory
+func (g *Game) rememberVisibleTiles(player *Player) {
+	if !player.Alive {
+		return
+	}
+	if player.memory == nil {
+		player.memory = make(map[[2]int]Terrain)
+	}
+	vision := g.visionRadius(player)
+	for dx := -vision; dx <= vision; dx++ {
+		for dy := -vision; dy <= vision; dy++ {
+			x, y := player.CurrentTile.XPos+dx, player.CurrentTile.YPos+dy
+			if g.gMap.isOnMap(x, y) {
+				player.memory[[2]int{x, y}] = g.gMap.gMap[x][y].Terrain
+			}
+		}
+	}
+}
+
+// RememberedTile is the terrain of a tile as a player last saw it
+type RememberedTile struct {
+	X       int
+	Y       int
+	Terrain Terrain
+}
+
+// rememberedTiles lists the player's memory ordered by position
+func (p *Player) rememberedTiles() []RememberedTile {
+	tiles := make([]RememberedTile, 0, len(p.memory))
+	for pos, terrain := range p.memory {
+		tiles = append(tiles, RememberedTile{pos[0], pos[1], terrain})
+	}
+	sort.Slice(tiles, func(i, j int) bool {
+		if tiles[i].X != tiles[j].X {
+			return tiles[i].X < tiles[j].X
+		}
+		return tiles[i].Y < tiles[j].Y
+	})
+	return tiles
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVision(t *testing.T) {
+	t.Run("grid has relative coordinates and live counts", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Player.VisionRadius = 2
+		ts.setupTile(5, 5, Farm, 0)
+		ts.setupTile(6, 3, City, 4)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+
+		view := ts.game.playerView(player)
+
+		assert.Equal(t, 2, view.Radius)
+		assert.Equal(t, 2, view.VisionRadius)
+		require.Len(t, view.Tiles, 5)
+		for _, row := range view.Tiles {
+			require.Len(t, row, 5)
+		}
+		northEast := view.Tiles[0][3]
+		assert.Equal(t, 1, northEast.DX)
+		assert.Equal(t, -2, northEast.DY)
+		assert.Equal(t, TileVisible, northEast.State)
+		assert.Equal(t, City.toString(), northEast.TileType)
+		assert.Equal(t, 4, northEast.ZombieCount)
+		assert.Equal(t, 1, view.Tiles[2][2].PlayerCount)
+	})
+
+	t.Run("terrain changes how far the player sees", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Player.VisionRadius = 1
+		ts.setupTile(5, 5, City, 0)
+		ts.setupTile(10, 10, Forest, 0)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+
+		view := ts.game.playerView(player)
+		assert.Equal(t, 2, view.Radius, "the grid grows with the extended vision")
+		assert.Equal(t, TileVisible, view.Tiles[0][0].State)
+
+		ts.movePlayerTo(player, 10, 10)
+		view = ts.game.playerView(player)
+		assert.Equal(t, 1, view.Radius, "the grid keeps the configured radius")
+		assert.Equal(t, 0, view.VisionRadius)
+		assert.Equal(t, TileVisible, view.Tiles[1][1].State)
+		assert.Equal(t, TileUnknown, view.Tiles[0][1].State)
+		assert.Empty(t, view.Tiles[0][1].TileType)
+	})
+
+	t.Run("remembered tiles keep their terrain but hide counts", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Player.VisionRadius = 2
+		ts.game.config.Player.TerrainVision = map[Terrain]int{Forest: -1}
+		ts.setupTile(5, 5, Farm, 0)
+		ts.setupTile(5, 6, Forest, 0)
+		ts.setupTile(5, 4, City, 3)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+
+		ts.game.pMap.teleport(player, 5, 6)
+		view := ts.game.playerView(player)
+
+		remembered := view.Tiles[0][2]
+		assert.Equal(t, 0, remembered.DX)
+		assert.Equal(t, -2, remembered.DY)
+		assert.Equal(t, TileRemembered, remembered.State)
+		assert.Equal(t, City.toString(), remembered.TileType)
+		assert.Zero(t, remembered.ZombieCount)
+	})
+
+	t.Run("memory is updated after every tick", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.quiet = true
+		ts.game.config.Player.VisionRadius = 1
+		ts.game.config.Player.TerrainVision = map[Terrain]int{}
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+		player.setHand([]Card{Food, Food, Food, Food})
+		player.Direction = South
+		ts.setupTile(5, 5, Farm, 0)
+		ts.setupTile(5, 6, Farm, 0)
+
+		ts.game.endTurn()
+
+		assert.Contains(t, player.memory, [2]int{5, 7})
+	})
+
+	t.Run("tiles off the map are visible edges", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		ts.game.config.Player.VisionRadius = 1
+		ts.game.config.Player.TerrainVision = map[Terrain]int{}
+		player := ts.getPlayer(ts.createPlayerAt(0, 0))
+
+		view := ts.game.playerView(player)
+
+		assert.Equal(t, Edge.toString(), view.Tiles[0][0].TileType)
+		assert.Equal(t, TileVisible, view.Tiles[0][0].State)
+	})
+
+	t.Run("memory survives a snapshot", func(t *testing.T) {
+		ts := setupTestSuite(t)
+		player := ts.getPlayer(ts.createPlayerAt(5, 5))
+
+		restored, err := RestoreGame(ts.game.snapshot())
+
+		require.NoError(t, err)
+		assert.Equal(t, player.memory, restored.pMap.Players[player.ID].memory)
+	})
+
+	t.Run("view endpoint requires the player's token", func(t *testing.T) {
+		as := setupAPITestSuite(t)
+		player := as.addPlayer("", "alice")
+
+		assert.Equal(t, http.StatusUnauthorized, as.request(http.MethodGet, "/player/"+player.ID+"/view", "").Code)
+		rec := as.requestWithToken(http.MethodGet, "/player/"+player.ID+"/view", "", player.Token)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var view View
+		as.decode(rec, &view)
+		assert.Len(t, view.Tiles, 2*view.Radius+1)
+		assert.Equal(t, TileVisible, view.Tiles[view.Radius][view.Radius].State)
+	})
+
+	t.Run("config caps the vision radius at the map size", func(t *testing.T) {
+		config := NewDefaultConfig()
+		config.Player.VisionRadius = config.Map.Width
+		config.Player.TerrainVision[City] = 1
+
+		err := config.Validate()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Player.VisionRadius")
+	})
+}